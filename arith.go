@@ -0,0 +1,223 @@
+package decimal
+
+import (
+	"math/big"
+
+	"github.com/ingothierack/decimal128"
+)
+
+// Parse parses s into a Decimal. It accepts the same input as decimal128.Parse.
+func Parse(s string) (Decimal, error) {
+	dd, err := decimal128.Parse(s)
+	return Decimal(dd), err
+}
+
+// MustParse is like Parse but panics if s cannot be parsed.
+func MustParse(s string) Decimal {
+	return Decimal(decimal128.MustParse(s))
+}
+
+// New returns the Decimal sig * 10^exp.
+func New(sig int64, exp int) Decimal {
+	return Decimal(decimal128.New(sig, exp))
+}
+
+// FromInt64 returns i as a Decimal.
+func FromInt64(i int64) Decimal {
+	return Decimal(decimal128.FromInt64(i))
+}
+
+// Decimal128 returns d as a decimal128.Decimal.
+func (d Decimal) Decimal128() decimal128.Decimal {
+	return decimal128.Decimal(d)
+}
+
+// Add returns d + o.
+func (d Decimal) Add(o Decimal) Decimal {
+	return Decimal(decimal128.Decimal(d).Add(decimal128.Decimal(o)))
+}
+
+// AddWithMode returns d + o, rounding with mode.
+func (d Decimal) AddWithMode(o Decimal, mode decimal128.RoundingMode) Decimal {
+	return Decimal(decimal128.Decimal(d).AddWithMode(decimal128.Decimal(o), mode))
+}
+
+// Sub returns d - o.
+func (d Decimal) Sub(o Decimal) Decimal {
+	return Decimal(decimal128.Decimal(d).Sub(decimal128.Decimal(o)))
+}
+
+// SubWithMode returns d - o, rounding with mode.
+func (d Decimal) SubWithMode(o Decimal, mode decimal128.RoundingMode) Decimal {
+	return Decimal(decimal128.Decimal(d).SubWithMode(decimal128.Decimal(o), mode))
+}
+
+// Mul returns d * o.
+func (d Decimal) Mul(o Decimal) Decimal {
+	return Decimal(decimal128.Decimal(d).Mul(decimal128.Decimal(o)))
+}
+
+// MulWithMode returns d * o, rounding with mode.
+func (d Decimal) MulWithMode(o Decimal, mode decimal128.RoundingMode) Decimal {
+	return Decimal(decimal128.Decimal(d).MulWithMode(decimal128.Decimal(o), mode))
+}
+
+// Quo returns d / o.
+func (d Decimal) Quo(o Decimal) Decimal {
+	return Decimal(decimal128.Decimal(d).Quo(decimal128.Decimal(o)))
+}
+
+// QuoWithMode returns d / o, rounding with mode.
+func (d Decimal) QuoWithMode(o Decimal, mode decimal128.RoundingMode) Decimal {
+	return Decimal(decimal128.Decimal(d).QuoWithMode(decimal128.Decimal(o), mode))
+}
+
+// QuoRem returns the integer quotient and the remainder of d / o.
+func (d Decimal) QuoRem(o Decimal) (Decimal, Decimal) {
+	q, r := decimal128.Decimal(d).QuoRem(decimal128.Decimal(o))
+	return Decimal(q), Decimal(r)
+}
+
+// QuoRemWithMode returns the integer quotient and the remainder of d / o,
+// rounding with mode.
+func (d Decimal) QuoRemWithMode(o Decimal, mode decimal128.RoundingMode) (Decimal, Decimal) {
+	q, r := decimal128.Decimal(d).QuoRemWithMode(decimal128.Decimal(o), mode)
+	return Decimal(q), Decimal(r)
+}
+
+// Pow returns d raised to the power o.
+func (d Decimal) Pow(o Decimal) Decimal {
+	return Decimal(decimal128.Decimal(d).Pow(decimal128.Decimal(o)))
+}
+
+// PowWithMode returns d raised to the power o, rounding with mode.
+func (d Decimal) PowWithMode(o Decimal, mode decimal128.RoundingMode) Decimal {
+	return Decimal(decimal128.Decimal(d).PowWithMode(decimal128.Decimal(o), mode))
+}
+
+// Neg returns -d.
+func (d Decimal) Neg() Decimal {
+	return Decimal(decimal128.Decimal(d).Neg())
+}
+
+// Abs returns the absolute value of d.
+func (d Decimal) Abs() Decimal {
+	return Decimal(decimal128.Abs(decimal128.Decimal(d)))
+}
+
+// Cmp compares d and o. See decimal128.Decimal.Cmp for the handling of NaN.
+func (d Decimal) Cmp(o Decimal) decimal128.CmpResult {
+	return decimal128.Decimal(d).Cmp(decimal128.Decimal(o))
+}
+
+// CmpAbs compares the absolute values of d and o.
+func (d Decimal) CmpAbs(o Decimal) decimal128.CmpResult {
+	return decimal128.Decimal(d).CmpAbs(decimal128.Decimal(o))
+}
+
+// Compare returns -1, 0 or +1 depending on whether d is less than, equal to or
+// greater than o. NaN is less than any other value, as in decimal128.Compare.
+func (d Decimal) Compare(o Decimal) int {
+	return decimal128.Compare(decimal128.Decimal(d), decimal128.Decimal(o))
+}
+
+// Equal reports whether d and o are numerically equal.
+func (d Decimal) Equal(o Decimal) bool {
+	return decimal128.Decimal(d).Equal(decimal128.Decimal(o))
+}
+
+// Sign returns -1, 0 or +1 depending on the sign of d. It panics if d is NaN.
+func (d Decimal) Sign() int {
+	return decimal128.Decimal(d).Sign()
+}
+
+// Signbit reports whether d is negative or negative zero.
+func (d Decimal) Signbit() bool {
+	return decimal128.Decimal(d).Signbit()
+}
+
+// IsZero reports whether d is ±0.
+func (d Decimal) IsZero() bool {
+	return decimal128.Decimal(d).IsZero()
+}
+
+// IsNaN reports whether d is NaN.
+func (d Decimal) IsNaN() bool {
+	return decimal128.Decimal(d).IsNaN()
+}
+
+// IsInf reports whether d is an infinity, using the sign convention of
+// math.IsInf.
+func (d Decimal) IsInf(sign int) bool {
+	return decimal128.Decimal(d).IsInf(sign)
+}
+
+// Round rounds d to dp digits after the decimal point using mode.
+func (d Decimal) Round(dp int, mode decimal128.RoundingMode) Decimal {
+	return Decimal(decimal128.Decimal(d).Round(dp, mode))
+}
+
+// Ceil rounds d towards positive infinity to dp digits after the decimal point.
+func (d Decimal) Ceil(dp int) Decimal {
+	return Decimal(decimal128.Decimal(d).Ceil(dp))
+}
+
+// Floor rounds d towards negative infinity to dp digits after the decimal
+// point.
+func (d Decimal) Floor(dp int) Decimal {
+	return Decimal(decimal128.Decimal(d).Floor(dp))
+}
+
+// Trunc rounds d towards zero to dp digits after the decimal point.
+func (d Decimal) Trunc(dp int) Decimal {
+	return d.Round(dp, decimal128.ToZero)
+}
+
+// Quantize rounds d to dp digits after the decimal point using mode and pads
+// it with trailing zeros so that it has exactly dp digits after the decimal
+// point, as far as the 34 digit precision allows. Zero has no scale in
+// decimal128 and is returned unpadded.
+func (d Decimal) Quantize(dp int, mode decimal128.RoundingMode) Decimal {
+	dd := decimal128.Decimal(d).Round(dp, mode)
+	if dd.IsNaN() || dd.IsInf(0) || dd.IsZero() {
+		return Decimal(dd)
+	}
+
+	_, neg, sig, exp := dd.Decompose(nil)
+	shift := int(exp) + dp
+	if shift <= 0 {
+		return Decimal(dd)
+	}
+
+	z := new(big.Int).SetBytes(sig)
+	z.Mul(z, new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(shift)), nil))
+
+	var out decimal128.Decimal
+	if err := out.Compose(0, neg, z.Bytes(), int32(-dp)); err != nil {
+		return Decimal(dd)
+	}
+
+	return Decimal(out)
+}
+
+// Canonical returns d with trailing zeros removed from its significand.
+func (d Decimal) Canonical() Decimal {
+	return Decimal(decimal128.Decimal(d).Canonical())
+}
+
+// Float64 returns the nearest float64 to d.
+func (d Decimal) Float64() float64 {
+	return decimal128.Decimal(d).Float64()
+}
+
+// Int64 returns d truncated towards zero and whether the result fit in an
+// int64. It panics if d is NaN.
+func (d Decimal) Int64() (int64, bool) {
+	return decimal128.Decimal(d).Int64()
+}
+
+// String returns d in plain notation with its scale preserved, spelling NaN
+// and infinite values the way PostgreSQL does.
+func (d Decimal) String() string {
+	return string(appendDecimalText(nil, decimal128.Decimal(d)))
+}
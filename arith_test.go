@@ -0,0 +1,80 @@
+package decimal_test
+
+import (
+	"testing"
+
+	"github.com/ingothierack/decimal128"
+	pgxdecimal "github.com/ingothierack/pgx-woodsbury-decimal128"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecimalArithmetic(t *testing.T) {
+	a := pgxdecimal.MustParse("10.50")
+	b := pgxdecimal.MustParse("4")
+
+	require.Equal(t, "14.50", a.Add(b).String())
+	require.Equal(t, "6.50", a.Sub(b).String())
+	require.Equal(t, "42.00", a.Mul(b).String())
+	require.True(t, a.Quo(b).Equal(pgxdecimal.MustParse("2.625")))
+	require.Equal(t, "-10.50", a.Neg().String())
+	require.Equal(t, "10.50", a.Neg().Abs().String())
+	require.True(t, b.Pow(pgxdecimal.FromInt64(2)).Equal(pgxdecimal.FromInt64(16)))
+
+	q, r := a.QuoRem(b)
+	require.True(t, q.Equal(pgxdecimal.FromInt64(2)))
+	require.True(t, r.Equal(pgxdecimal.MustParse("2.5")))
+
+	third := pgxdecimal.FromInt64(1).QuoWithMode(pgxdecimal.FromInt64(3), decimal128.ToZero)
+	require.Equal(t, "0.3333333333333333333333333333333333", third.String())
+}
+
+func TestDecimalComparison(t *testing.T) {
+	a := pgxdecimal.MustParse("1.0")
+	b := pgxdecimal.MustParse("1.00")
+	c := pgxdecimal.MustParse("-2")
+
+	require.True(t, a.Equal(b))
+	require.True(t, a.Cmp(b).Equal())
+	require.True(t, c.Cmp(a).Less())
+	require.True(t, c.CmpAbs(a).Greater())
+	require.Equal(t, 1, a.Compare(c))
+	require.Equal(t, -1, pgxdecimal.Decimal(decimal128.NaN()).Compare(c))
+	require.Equal(t, -1, c.Sign())
+	require.True(t, c.Signbit())
+	require.True(t, pgxdecimal.New(0, 2).IsZero())
+	require.True(t, pgxdecimal.Decimal(decimal128.NaN()).IsNaN())
+	require.True(t, pgxdecimal.Decimal(decimal128.Inf(-1)).IsInf(-1))
+}
+
+func TestDecimalRounding(t *testing.T) {
+	d := pgxdecimal.MustParse("-2.345")
+
+	require.Equal(t, "-2.35", d.Round(2, decimal128.ToNearestAway).String())
+	require.Equal(t, "-2.34", d.Round(2, decimal128.ToNearestEven).String())
+	require.Equal(t, "-2.34", d.Ceil(2).String())
+	require.Equal(t, "-2.35", d.Floor(2).String())
+	require.Equal(t, "-2.3", d.Trunc(1).String())
+
+	require.Equal(t, "-2.3450", d.Quantize(4, decimal128.ToNearestEven).String())
+	require.Equal(t, "12.00", pgxdecimal.FromInt64(12).Quantize(2, decimal128.ToNearestEven).String())
+	require.Equal(t, "1.01", pgxdecimal.MustParse("1.005").Quantize(2, decimal128.ToNearestAway).String())
+	require.Equal(t, "1200", pgxdecimal.MustParse("1234").Quantize(-2, decimal128.ToNearestEven).String())
+	require.Equal(t, "1", pgxdecimal.MustParse("1.000").Canonical().String())
+}
+
+func TestDecimalConversion(t *testing.T) {
+	d, err := pgxdecimal.Parse("123.456")
+	require.NoError(t, err)
+	require.Equal(t, decimal128.MustParse("123.456"), d.Decimal128())
+	require.Equal(t, 123.456, d.Float64())
+
+	i, ok := d.Int64()
+	require.True(t, ok)
+	require.Equal(t, int64(123), i)
+
+	_, err = pgxdecimal.Parse("abc")
+	require.Error(t, err)
+
+	require.Equal(t, "0.000012345", pgxdecimal.New(12345, -9).String())
+	require.Equal(t, "NaN", pgxdecimal.Decimal(decimal128.NaN()).String())
+}
@@ -0,0 +1,92 @@
+package decimal
+
+import (
+	"encoding/binary"
+	"errors"
+
+	"github.com/ingothierack/decimal128"
+)
+
+var errInvalidBSONDecimal128 = errors.New("invalid BSON Decimal128: length must be 16")
+
+// BSON Decimal128 NaN and infinity as MongoDB writes them.
+const (
+	bsonNaNHi    = 0x7c00_0000_0000_0000
+	bsonPosInfHi = 0x7800_0000_0000_0000
+	bsonNegInfHi = 0xf800_0000_0000_0000
+)
+
+// BSONDecimal128Bits returns the high and low 64 bits of the BSON Decimal128
+// encoding of d, in the order primitive.NewDecimal128 of the MongoDB Go driver
+// takes them. The conversion is lossless for finite values, except that
+// decimal128 does not keep the exponent of a parsed zero; such zeros are
+// written with exponent 0. NaN is written without payload as MongoDB does.
+func BSONDecimal128Bits(d decimal128.Decimal) (hi, lo uint64) {
+	switch {
+	case d.IsNaN():
+		return bsonNaNHi, 0
+	case d.IsInf(1):
+		return bsonPosInfHi, 0
+	case d.IsInf(-1):
+		return bsonNegInfHi, 0
+	}
+
+	return bidBits(d)
+}
+
+// FromBSONDecimal128Bits returns the Decimal with the BSON Decimal128
+// encoding hi, lo, as returned by GetBytes of the MongoDB Go driver's
+// primitive.Decimal128. Any NaN, including signaling and negative NaN, becomes
+// decimal128.NaN(), and non-canonical significands decode as zero.
+func FromBSONDecimal128Bits(hi, lo uint64) decimal128.Decimal {
+	switch {
+	case hi&decimal128NaNBits == decimal128NaNBits:
+		return decimal128.NaN()
+	case hi&decimal128NaNBits == decimal128InfBits:
+		return decimal128.Inf(1 - int(hi>>63)*2)
+	}
+
+	return decimalFromBID(hi, lo)
+}
+
+// AppendBSONDecimal128 appends the 16 byte BSON Decimal128 encoding of d to
+// buf: IEEE 754 BID in little-endian byte order, as stored in BSON documents.
+func AppendBSONDecimal128(buf []byte, d decimal128.Decimal) []byte {
+	hi, lo := BSONDecimal128Bits(d)
+	return binary.LittleEndian.AppendUint64(binary.LittleEndian.AppendUint64(buf, lo), hi)
+}
+
+// DecodeBSONDecimal128 decodes 16 bytes of BSON Decimal128.
+func DecodeBSONDecimal128(b []byte) (decimal128.Decimal, error) {
+	if len(b) != 16 {
+		return decimal128.Decimal{}, errInvalidBSONDecimal128
+	}
+
+	return FromBSONDecimal128Bits(binary.LittleEndian.Uint64(b[8:]), binary.LittleEndian.Uint64(b)), nil
+}
+
+// BSONDecimal128 returns the BSON Decimal128 encoding of d, or nil if d is
+// NULL.
+func (d NullDecimal) BSONDecimal128() []byte {
+	if !d.Valid {
+		return nil
+	}
+
+	return AppendBSONDecimal128(make([]byte, 0, 16), d.Decimal)
+}
+
+// ScanBSONDecimal128 sets d from BSON Decimal128 bytes. nil sets d to NULL.
+func (d *NullDecimal) ScanBSONDecimal128(b []byte) error {
+	if b == nil {
+		*d = NullDecimal{}
+		return nil
+	}
+
+	dd, err := DecodeBSONDecimal128(b)
+	if err != nil {
+		return err
+	}
+
+	*d = NullDecimal{Decimal: dd, Valid: true}
+	return nil
+}
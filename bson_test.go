@@ -0,0 +1,82 @@
+package decimal_test
+
+import (
+	"encoding/hex"
+	"strings"
+	"testing"
+
+	"github.com/ingothierack/decimal128"
+	pgxdecimal "github.com/ingothierack/pgx-woodsbury-decimal128"
+	"github.com/stretchr/testify/require"
+)
+
+// Vectors from the BSON specification's decimal128 test corpus.
+var bsonDecimal128Tests = []struct {
+	bson  string
+	value string
+}{
+	{"00000000000000000000000000004030", "0"},
+	{"000000000000000000000000000040b0", "-0"},
+	{"01000000000000000000000000004030", "1"},
+	{"01000000000000000000000000003e30", "0.1"},
+	{"f2af967ed05c82de3297ff6fde3c4030", "1234567890123456789012345678901234"},
+	{"ffffffff638e8d37c087adbe09edff5f", "9.999999999999999999999999999999999e6144"},
+	{"01000000000000000000000000000000", "1e-6176"},
+	{"00000000000000000000000000000078", "Infinity"},
+	{"000000000000000000000000000000f8", "-Infinity"},
+	{"0000000000000000000000000000007c", "NaN"},
+}
+
+func TestBSONDecimal128(t *testing.T) {
+	for _, tt := range bsonDecimal128Tests {
+		b, err := hex.DecodeString(tt.bson)
+		require.NoError(t, err)
+
+		d, err := pgxdecimal.DecodeBSONDecimal128(b)
+		require.NoError(t, err)
+		require.True(t, pgxdecimal.CompareNumeric(decimal128.MustParse(tt.value), d) == 0, tt.value)
+		require.Equal(t, strings.HasPrefix(tt.value, "-"), d.Signbit(), tt.value)
+
+		require.Equal(t, tt.bson, hex.EncodeToString(pgxdecimal.AppendBSONDecimal128(nil, d)), tt.value)
+	}
+}
+
+func TestBSONDecimal128Bits(t *testing.T) {
+	d := decimal128.MustParse("-123.45")
+	hi, lo := pgxdecimal.BSONDecimal128Bits(d)
+	require.Equal(t, d.String(), pgxdecimal.FromBSONDecimal128Bits(hi, lo).String())
+
+	// Signaling and negative NaN decode as NaN.
+	require.True(t, pgxdecimal.FromBSONDecimal128Bits(0x7e00_0000_0000_0000, 0).IsNaN())
+	require.True(t, pgxdecimal.FromBSONDecimal128Bits(0xfc00_0000_0000_0000, 12).IsNaN())
+
+	// A parsed zero has no exponent and is written as 0E0, not 0E-6176.
+	require.Equal(t, "00000000000000000000000000004030", hex.EncodeToString(pgxdecimal.AppendBSONDecimal128(nil, decimal128.MustParse("0"))))
+	require.Equal(t, "000000000000000000000000000040b0", hex.EncodeToString(pgxdecimal.AppendBSONDecimal128(nil, decimal128.MustParse("-0.00"))))
+
+	hi, lo = pgxdecimal.BSONDecimal128Bits(decimal128.NaN())
+	require.Equal(t, uint64(0x7c00_0000_0000_0000), hi)
+	require.Zero(t, lo)
+
+	// A significand above 10^34 - 1 is non-canonical and decodes as zero.
+	require.True(t, pgxdecimal.FromBSONDecimal128Bits(0x3041_ed09_bead_87c0, 0x378d_8e64_0000_0000).IsZero())
+	require.True(t, pgxdecimal.FromBSONDecimal128Bits(0x6c10_0000_0000_0000, 0).IsZero())
+}
+
+func TestNullDecimalBSONDecimal128(t *testing.T) {
+	require.Nil(t, pgxdecimal.NullDecimal{}.BSONDecimal128())
+
+	nd := pgxdecimal.NullDecimal{Decimal: decimal128.MustParse("42.5"), Valid: true}
+	b := nd.BSONDecimal128()
+	require.Len(t, b, 16)
+
+	var result pgxdecimal.NullDecimal
+	require.NoError(t, result.ScanBSONDecimal128(b))
+	require.True(t, result.Valid)
+	require.Equal(t, "42.5", result.Decimal.String())
+
+	require.NoError(t, result.ScanBSONDecimal128(nil))
+	require.False(t, result.Valid)
+
+	require.Error(t, result.ScanBSONDecimal128(b[:8]))
+}
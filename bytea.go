@@ -0,0 +1,426 @@
+package decimal
+
+import (
+	"encoding/binary"
+	"errors"
+	"math/big"
+	"strings"
+
+	"github.com/ingothierack/decimal128"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+var errInvalidDecimal128Bytes = errors.New("invalid IEEE 754 decimal128 bytes: length must be 16")
+
+// ByteaEncoding is a 16 byte IEEE 754-2008 decimal128 interchange encoding,
+// stored in big-endian byte order.
+type ByteaEncoding int
+
+const (
+	// BID is the binary integer decimal encoding, which decimal128.Decimal
+	// uses in memory.
+	BID ByteaEncoding = iota
+
+	// DPD is the densely packed decimal encoding, used by IBM hardware and
+	// databases.
+	DPD
+)
+
+// decimal128 layout constants shared by BID and DPD.
+const (
+	decimal128SignBit     = 1 << 63
+	decimal128SpecialMask = 0x7800_0000_0000_0000
+	decimal128InfBits     = 0x7800_0000_0000_0000
+	decimal128NaNBits     = 0x7c00_0000_0000_0000
+
+	// decimal128ZeroBits is the high word of 0, with exponent 0.
+	decimal128ZeroBits = 0x3040_0000_0000_0000
+)
+
+// AppendDecimal appends the 16 byte encoding of d to buf. The scale of d is
+// preserved, except that decimal128 does not keep the exponent of a parsed
+// zero; such zeros are written with exponent 0.
+func (e ByteaEncoding) AppendDecimal(buf []byte, d decimal128.Decimal) []byte {
+	hi, lo := bidBits(d)
+	if e == DPD {
+		hi, lo = bidToDPD(hi, lo)
+	}
+	return binary.BigEndian.AppendUint64(binary.BigEndian.AppendUint64(buf, hi), lo)
+}
+
+// DecodeDecimal decodes 16 bytes in encoding e. Non-canonical significands,
+// which IEEE 754 defines to be zero, decode as zero.
+func (e ByteaEncoding) DecodeDecimal(b []byte) (decimal128.Decimal, error) {
+	if len(b) != 16 {
+		return decimal128.Decimal{}, errInvalidDecimal128Bytes
+	}
+
+	hi, lo := binary.BigEndian.Uint64(b), binary.BigEndian.Uint64(b[8:])
+	if e == DPD {
+		hi, lo = dpdToBID(hi, lo)
+	}
+	return decimalFromBID(hi, lo), nil
+}
+
+// bidBits returns the high and low 64 bits of the BID encoding of d. A zero
+// without exponent, which decimal128 stores as all zero bits and IEEE 754
+// reads as 0E-6176, gets exponent 0.
+func bidBits(d decimal128.Decimal) (hi, lo uint64) {
+	b, _ := d.MarshalBinary()
+	hi, lo = binary.BigEndian.Uint64(b), binary.BigEndian.Uint64(b[8:])
+	if hi&^decimal128SignBit == 0 && lo == 0 {
+		hi |= decimal128ZeroBits
+	}
+	return hi, lo
+}
+
+// decimalFromBID returns the Decimal with the BID encoding hi, lo, with
+// non-canonical significands replaced by zero.
+func decimalFromBID(hi, lo uint64) decimal128.Decimal {
+	hi, lo = canonicalBID(hi, lo)
+
+	var d decimal128.Decimal
+	_ = d.UnmarshalBinary(binary.BigEndian.AppendUint64(binary.BigEndian.AppendUint64(nil, hi), lo))
+	return d
+}
+
+// bidFields splits a finite BID value into its sign, biased exponent and
+// significand. ok is false for NaN and infinities.
+func bidFields(hi, lo uint64) (sign uint64, exp int, coef *big.Int, ok bool) {
+	sign = hi & decimal128SignBit
+	if hi&decimal128SpecialMask == decimal128SpecialMask {
+		return sign, 0, nil, false
+	}
+
+	coef = new(big.Int)
+	if hi&0x6000_0000_0000_0000 == 0x6000_0000_0000_0000 {
+		// The significand would be at least 2^113, which is non-canonical.
+		exp = int(hi >> 47 & 0x3fff)
+	} else {
+		exp = int(hi >> 49 & 0x3fff)
+		coef.SetUint64(hi & 0x0001_ffff_ffff_ffff)
+		coef.Lsh(coef, 64).Or(coef, new(big.Int).SetUint64(lo))
+		if coef.Cmp(maxCoefficient) > 0 {
+			coef.SetInt64(0)
+		}
+	}
+
+	return sign, exp, coef, true
+}
+
+// composeBID returns the BID encoding of a finite value. coef must have at
+// most 34 digits.
+func composeBID(sign uint64, exp int, coef *big.Int) (uint64, uint64) {
+	var b [16]byte
+	coef.FillBytes(b[:])
+	hi := binary.BigEndian.Uint64(b[:])
+	lo := binary.BigEndian.Uint64(b[8:])
+	return sign | uint64(exp)<<49 | hi, lo
+}
+
+func canonicalBID(hi, lo uint64) (uint64, uint64) {
+	sign, exp, coef, ok := bidFields(hi, lo)
+	if !ok {
+		return hi, lo
+	}
+	return composeBID(sign, exp, coef)
+}
+
+// bidToDPD converts a BID value to DPD.
+func bidToDPD(hi, lo uint64) (uint64, uint64) {
+	sign, exp, coef, ok := bidFields(hi, lo)
+	if !ok {
+		// The combination field of NaN and infinities is the same in both
+		// encodings. Payloads are not carried over.
+		if hi&decimal128NaNBits == decimal128NaNBits {
+			return sign | hi&0x7e00_0000_0000_0000, 0
+		}
+		return sign | decimal128InfBits, 0
+	}
+
+	digits := coef.String()
+	digits = strings.Repeat("0", 34-len(digits)) + digits
+	msd := int(digits[0] - '0')
+
+	var comb uint64
+	if msd < 8 {
+		comb = uint64(exp>>12)<<3 | uint64(msd)
+	} else {
+		comb = 0b11000 | uint64(exp>>12)<<1 | uint64(msd&1)
+	}
+
+	// 11 declets of 3 digits fill the low 110 bits.
+	var declets [11]uint64
+	for i := range declets {
+		s := digits[1+3*i:]
+		declets[i] = encodeDeclet(int(s[0]-'0'), int(s[1]-'0'), int(s[2]-'0'))
+	}
+
+	hi, lo = sign|comb<<58|uint64(exp&0xfff)<<46, 0
+	for i, d := range declets {
+		shift := 100 - 10*i
+		if shift >= 64 {
+			hi |= d << (shift - 64)
+		} else {
+			lo |= d << shift
+			if shift > 54 {
+				hi |= d >> (64 - shift)
+			}
+		}
+	}
+
+	return hi, lo
+}
+
+// dpdToBID converts a DPD value to BID.
+func dpdToBID(hi, lo uint64) (uint64, uint64) {
+	sign := hi & decimal128SignBit
+	comb := hi >> 58 & 0x1f
+
+	switch {
+	case comb == 0b11111:
+		return sign | hi&0x7e00_0000_0000_0000, 0
+	case comb == 0b11110:
+		return sign | decimal128InfBits, 0
+	}
+
+	var exp int
+	var msd uint64
+	if comb>>3 != 0b11 {
+		exp = int(comb >> 3)
+		msd = comb & 0b111
+	} else {
+		exp = int(comb >> 1 & 0b11)
+		msd = 8 + comb&1
+	}
+	exp = exp<<12 | int(hi>>46&0xfff)
+
+	coef := new(big.Int).SetUint64(msd)
+	thousand := big.NewInt(1000)
+	for i := range 11 {
+		shift := 100 - 10*i
+		var d uint64
+		if shift >= 64 {
+			d = hi >> (shift - 64)
+		} else {
+			d = lo >> shift
+			if shift > 54 {
+				d |= hi << (64 - shift)
+			}
+		}
+		coef.Mul(coef, thousand).Add(coef, big.NewInt(int64(decodeDeclet(d&0x3ff))))
+	}
+
+	return composeBID(sign, exp, coef)
+}
+
+// encodeDeclet packs three decimal digits into 10 bits of densely packed
+// decimal.
+func encodeDeclet(d2, d1, d0 int) uint64 {
+	// Bits of the digits, from the most significant.
+	b := func(d, bit int) uint64 { return uint64(d >> bit & 1) }
+	large := d2>>3<<2 | d1>>3<<1 | d0>>3
+
+	var hi3, mid3, low4 uint64
+	switch large {
+	case 0b000:
+		hi3, mid3, low4 = uint64(d2), uint64(d1), uint64(d0)
+	case 0b001:
+		hi3, mid3, low4 = uint64(d2), uint64(d1), 0b1000|b(d0, 0)
+	case 0b010:
+		hi3, mid3, low4 = uint64(d2), uint64(d0&0b110|d1&1), 0b1010|b(d0, 0)
+	case 0b100:
+		hi3, mid3, low4 = uint64(d0&0b110|d2&1), uint64(d1), 0b1100|b(d0, 0)
+	case 0b011:
+		hi3, mid3, low4 = uint64(d2), 0b100|b(d1, 0), 0b1110|b(d0, 0)
+	case 0b101:
+		hi3, mid3, low4 = uint64(d1&0b110|d2&1), 0b010|b(d1, 0), 0b1110|b(d0, 0)
+	case 0b110:
+		hi3, mid3, low4 = uint64(d0&0b110|d2&1), 0b000|b(d1, 0), 0b1110|b(d0, 0)
+	default:
+		hi3, mid3, low4 = b(d2, 0), 0b110|b(d1, 0), 0b1110|b(d0, 0)
+	}
+
+	return hi3<<7 | mid3<<4 | low4
+}
+
+// decodeDeclet unpacks 10 bits of densely packed decimal into a number from 0
+// to 999. The 24 non-canonical declets decode like their canonical forms.
+func decodeDeclet(d uint64) int {
+	pqr := int(d >> 7 & 0b111)
+	stu := int(d >> 4 & 0b111)
+	wxy := int(d & 0b111)
+	r, u, y := pqr&1, stu&1, wxy&1
+
+	var d2, d1, d0 int
+	switch {
+	case d>>3&1 == 0:
+		d2, d1, d0 = pqr, stu, wxy
+	case wxy>>1 == 0b00:
+		d2, d1, d0 = pqr, stu, 8|y
+	case wxy>>1 == 0b01:
+		d2, d1, d0 = pqr, 8|u, stu&0b110|y
+	case wxy>>1 == 0b10:
+		d2, d1, d0 = 8|r, stu, pqr&0b110|y
+	case stu>>1 == 0b00:
+		d2, d1, d0 = 8|r, 8|u, pqr&0b110|y
+	case stu>>1 == 0b01:
+		d2, d1, d0 = 8|r, pqr&0b110|u, 8|y
+	case stu>>1 == 0b10:
+		d2, d1, d0 = pqr, 8|u, 8|y
+	default:
+		d2, d1, d0 = 8|r, 8|u, 8|y
+	}
+
+	return d2*100 + d1*10 + d0
+}
+
+// BIDDecimal is a NullDecimal that is stored in bytea columns as 16 bytes of
+// IEEE 754 decimal128 in BID encoding. It implements pgtype.BytesScanner and
+// pgtype.BytesValuer, so it works with pgx's bytea codec without
+// registration; NULL is stored as NULL.
+type BIDDecimal NullDecimal
+
+// ScanBytes implements the pgtype.BytesScanner interface.
+func (d *BIDDecimal) ScanBytes(v []byte) error {
+	return (*NullDecimal)(d).scanBytea(v, BID)
+}
+
+// BytesValue implements the pgtype.BytesValuer interface.
+func (d BIDDecimal) BytesValue() ([]byte, error) {
+	return NullDecimal(d).byteaValue(BID), nil
+}
+
+// DPDDecimal is like BIDDecimal with DPD encoding.
+type DPDDecimal NullDecimal
+
+// ScanBytes implements the pgtype.BytesScanner interface.
+func (d *DPDDecimal) ScanBytes(v []byte) error {
+	return (*NullDecimal)(d).scanBytea(v, DPD)
+}
+
+// BytesValue implements the pgtype.BytesValuer interface.
+func (d DPDDecimal) BytesValue() ([]byte, error) {
+	return NullDecimal(d).byteaValue(DPD), nil
+}
+
+func (d *NullDecimal) scanBytea(v []byte, e ByteaEncoding) error {
+	if v == nil {
+		*d = NullDecimal{}
+		return nil
+	}
+
+	dd, err := e.DecodeDecimal(v)
+	if err != nil {
+		return err
+	}
+
+	*d = NullDecimal{Decimal: dd, Valid: true}
+	return nil
+}
+
+func (d NullDecimal) byteaValue(e ByteaEncoding) []byte {
+	if !d.Valid {
+		return nil
+	}
+
+	return e.AppendDecimal(make([]byte, 0, 16), d.Decimal)
+}
+
+// ByteaCodec is a pgtype.Codec for bytea that also encodes and scans
+// decimal128.Decimal, Decimal and NullDecimal values as 16 bytes of IEEE 754
+// decimal128 in the given encoding. Other values are handled by
+// pgtype.ByteaCodec, and DecodeValue still returns []byte.
+type ByteaCodec struct {
+	pgtype.ByteaCodec
+	Encoding ByteaEncoding
+}
+
+// RegisterBytea replaces the bytea codec of m with a ByteaCodec using
+// encoding e, so decimal values bound to or scanned from bytea columns use
+// the 16 byte encoding. numeric columns are not affected. To opt in per value
+// instead, use BIDDecimal or DPDDecimal.
+func RegisterBytea(m *pgtype.Map, e ByteaEncoding) {
+	byteaType := &pgtype.Type{Name: "bytea", OID: pgtype.ByteaOID, Codec: ByteaCodec{Encoding: e}}
+
+	m.RegisterType(byteaType)
+	m.RegisterType(&pgtype.Type{Name: "_bytea", OID: pgtype.ByteaArrayOID, Codec: &pgtype.ArrayCodec{ElementType: byteaType}})
+}
+
+func (c ByteaCodec) PlanEncode(m *pgtype.Map, oid uint32, format int16, value any) pgtype.EncodePlan {
+	switch value.(type) {
+	case decimal128.Decimal, Decimal, NullDecimal:
+		if next := c.ByteaCodec.PlanEncode(m, oid, format, []byte(nil)); next != nil {
+			return &encodePlanByteaDecimal{next: next, encoding: c.Encoding}
+		}
+		return nil
+	}
+
+	return c.ByteaCodec.PlanEncode(m, oid, format, value)
+}
+
+func (c ByteaCodec) PlanScan(m *pgtype.Map, oid uint32, format int16, target any) pgtype.ScanPlan {
+	switch target.(type) {
+	case *decimal128.Decimal, *Decimal, *NullDecimal:
+		if next := c.ByteaCodec.PlanScan(m, oid, format, (*[]byte)(nil)); next != nil {
+			return &scanPlanByteaDecimal{next: next, encoding: c.Encoding}
+		}
+		return nil
+	}
+
+	return c.ByteaCodec.PlanScan(m, oid, format, target)
+}
+
+type encodePlanByteaDecimal struct {
+	next     pgtype.EncodePlan
+	encoding ByteaEncoding
+}
+
+func (plan *encodePlanByteaDecimal) Encode(value any, buf []byte) (newBuf []byte, err error) {
+	var d NullDecimal
+	switch value := value.(type) {
+	case decimal128.Decimal:
+		d = NullDecimal{Decimal: value, Valid: true}
+	case Decimal:
+		d = NullDecimal{Decimal: decimal128.Decimal(value), Valid: true}
+	case NullDecimal:
+		d = value
+	}
+
+	return plan.next.Encode(d.byteaValue(plan.encoding), buf)
+}
+
+type scanPlanByteaDecimal struct {
+	next     pgtype.ScanPlan
+	encoding ByteaEncoding
+}
+
+func (plan *scanPlanByteaDecimal) Scan(src []byte, dst any) error {
+	var b []byte
+	if err := plan.next.Scan(src, &b); err != nil {
+		return err
+	}
+
+	var d NullDecimal
+	if err := d.scanBytea(b, plan.encoding); err != nil {
+		return err
+	}
+
+	switch dst := dst.(type) {
+	case *NullDecimal:
+		*dst = d
+		return nil
+	case *decimal128.Decimal:
+		if !d.Valid {
+			return errScanNull
+		}
+		*dst = d.Decimal
+	case *Decimal:
+		if !d.Valid {
+			return errScanNull
+		}
+		*dst = Decimal(d.Decimal)
+	}
+
+	return nil
+}
@@ -0,0 +1,171 @@
+package decimal_test
+
+import (
+	"context"
+	"encoding/hex"
+	"testing"
+
+	"github.com/ingothierack/decimal128"
+	pgxdecimal "github.com/ingothierack/pgx-woodsbury-decimal128"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+)
+
+var byteaTestValues = []string{
+	"0", "-0", "0.00", "0e6111", "1", "-1", "-7.50", "123456.789", "1e-6176",
+	"9999999999999999999999999999999999e6111", "-9999999999999999999999999999999999e-6176",
+	"8888888888888888888888888888888888", "1234567890123456789012345678901234e-40",
+	"Infinity", "-Infinity",
+}
+
+func TestByteaEncodingVectors(t *testing.T) {
+	tests := []struct {
+		value string
+		bid   string
+		dpd   string
+	}{
+		{"1", "30400000000000000000000000000001", "22080000000000000000000000000001"},
+		{"0", "30400000000000000000000000000000", "22080000000000000000000000000000"},
+		{"-0", "b0400000000000000000000000000000", "a2080000000000000000000000000000"},
+		{"-7.50", "b03c00000000000000000000000002ee", "a20780000000000000000000000003d0"},
+		{"Infinity", "78000000000000000000000000000000", "78000000000000000000000000000000"},
+		{"-Infinity", "f8000000000000000000000000000000", "f8000000000000000000000000000000"},
+	}
+
+	for _, tt := range tests {
+		d := decimal128.MustParse(tt.value)
+		require.Equal(t, tt.bid, hex.EncodeToString(pgxdecimal.BID.AppendDecimal(nil, d)), tt.value)
+		require.Equal(t, tt.dpd, hex.EncodeToString(pgxdecimal.DPD.AppendDecimal(nil, d)), tt.value)
+
+		b, err := hex.DecodeString(tt.dpd)
+		require.NoError(t, err)
+		result, err := pgxdecimal.DPD.DecodeDecimal(b)
+		require.NoError(t, err)
+		require.Equal(t, d.String(), result.String(), tt.value)
+	}
+}
+
+func TestByteaEncodingRoundTrip(t *testing.T) {
+	for _, e := range []pgxdecimal.ByteaEncoding{pgxdecimal.BID, pgxdecimal.DPD} {
+		for _, s := range byteaTestValues {
+			d := decimal128.MustParse(s)
+			b := e.AppendDecimal([]byte{0xff}, d)
+			require.Len(t, b, 17)
+
+			result, err := e.DecodeDecimal(b[1:])
+			require.NoError(t, err)
+			require.Equal(t, d.String(), result.String(), s)
+			require.Equal(t, d.Signbit(), result.Signbit(), s)
+		}
+
+		nan, err := e.DecodeDecimal(e.AppendDecimal(nil, decimal128.NaN()))
+		require.NoError(t, err)
+		require.True(t, nan.IsNaN())
+
+		_, err = e.DecodeDecimal(make([]byte, 15))
+		require.Error(t, err)
+	}
+}
+
+func TestByteaEncodingNonCanonical(t *testing.T) {
+	// BID significand of 10^34, which is out of range and decodes as zero.
+	b, err := hex.DecodeString("3041ed09bead87c0378d8e6400000000")
+	require.NoError(t, err)
+	d, err := pgxdecimal.BID.DecodeDecimal(b)
+	require.NoError(t, err)
+	require.True(t, d.IsZero())
+
+	// DPD declet 0x3ff is a non-canonical form of 999.
+	b, err = hex.DecodeString("220800000000000000000000000003ff")
+	require.NoError(t, err)
+	d, err = pgxdecimal.DPD.DecodeDecimal(b)
+	require.NoError(t, err)
+	require.Equal(t, "999", d.String())
+}
+
+func TestByteaCodec(t *testing.T) {
+	for _, e := range []pgxdecimal.ByteaEncoding{pgxdecimal.BID, pgxdecimal.DPD} {
+		m := pgtype.NewMap()
+		pgxdecimal.Register(m)
+		pgxdecimal.RegisterBytea(m, e)
+
+		for _, format := range []int16{pgtype.TextFormatCode, pgtype.BinaryFormatCode} {
+			d := decimal128.MustParse("-123.450")
+			buf, err := m.Encode(pgtype.ByteaOID, format, d, nil)
+			require.NoError(t, err)
+
+			var raw []byte
+			require.NoError(t, m.Scan(pgtype.ByteaOID, format, buf, &raw))
+			require.Equal(t, e.AppendDecimal(nil, d), raw)
+
+			var result decimal128.Decimal
+			require.NoError(t, m.Scan(pgtype.ByteaOID, format, buf, &result))
+			require.Equal(t, d.String(), result.String())
+
+			var resultDecimal pgxdecimal.Decimal
+			require.NoError(t, m.Scan(pgtype.ByteaOID, format, buf, &resultDecimal))
+			require.Equal(t, d.String(), decimal128.Decimal(resultDecimal).String())
+
+			buf, err = m.Encode(pgtype.ByteaOID, format, pgxdecimal.NullDecimal{}, nil)
+			require.NoError(t, err)
+			require.Nil(t, buf)
+
+			var resultNull pgxdecimal.NullDecimal
+			require.NoError(t, m.Scan(pgtype.ByteaOID, format, nil, &resultNull))
+			require.False(t, resultNull.Valid)
+			require.Error(t, m.Scan(pgtype.ByteaOID, format, nil, &result))
+		}
+
+		// numeric is not affected.
+		buf, err := m.Encode(pgtype.NumericOID, pgtype.TextFormatCode, decimal128.MustParse("1.5"), nil)
+		require.NoError(t, err)
+		require.Equal(t, "1.5", string(buf))
+	}
+}
+
+func TestBIDDecimalWrapper(t *testing.T) {
+	m := pgtype.NewMap()
+
+	for _, format := range []int16{pgtype.TextFormatCode, pgtype.BinaryFormatCode} {
+		d := decimal128.MustParse("42")
+		buf, err := m.Encode(pgtype.ByteaOID, format, pgxdecimal.BIDDecimal{Decimal: d, Valid: true}, nil)
+		require.NoError(t, err)
+
+		var bid pgxdecimal.BIDDecimal
+		require.NoError(t, m.Scan(pgtype.ByteaOID, format, buf, &bid))
+		require.True(t, bid.Valid)
+		require.Equal(t, "42", bid.Decimal.String())
+
+		buf, err = m.Encode(pgtype.ByteaOID, format, pgxdecimal.DPDDecimal{Decimal: d, Valid: true}, nil)
+		require.NoError(t, err)
+
+		var dpd pgxdecimal.DPDDecimal
+		require.NoError(t, m.Scan(pgtype.ByteaOID, format, buf, &dpd))
+		require.Equal(t, "42", dpd.Decimal.String())
+
+		buf, err = m.Encode(pgtype.ByteaOID, format, pgxdecimal.BIDDecimal{}, nil)
+		require.NoError(t, err)
+		require.Nil(t, buf)
+		require.NoError(t, m.Scan(pgtype.ByteaOID, format, nil, &bid))
+		require.False(t, bid.Valid)
+	}
+}
+
+func TestByteaMatchServer(t *testing.T) {
+	defaultConnTestRunner.RunTest(context.Background(), t, func(ctx context.Context, t testing.TB, conn *pgx.Conn) {
+		for _, s := range byteaTestValues {
+			d := decimal128.MustParse(s)
+
+			var hexBytes string
+			var bid, dpd pgxdecimal.NullDecimal
+			err := conn.QueryRow(ctx, "select encode($1::bytea, 'hex'), $1::bytea, $2::bytea",
+				pgxdecimal.BIDDecimal{Decimal: d, Valid: true}, pgxdecimal.DPDDecimal{Decimal: d, Valid: true},
+			).Scan(&hexBytes, (*pgxdecimal.BIDDecimal)(&bid), (*pgxdecimal.DPDDecimal)(&dpd))
+			require.NoError(t, err)
+			require.Equal(t, hex.EncodeToString(pgxdecimal.BID.AppendDecimal(nil, d)), hexBytes)
+			require.Equal(t, d.String(), bid.Decimal.String())
+			require.Equal(t, d.String(), dpd.Decimal.String())
+		}
+	})
+}
@@ -0,0 +1,285 @@
+package decimal
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/ingothierack/decimal128"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// CopyRowError is returned by CopyFrom for a row of the source that cannot be
+// copied.
+type CopyRowError struct {
+	// Row is the index of the row in the source, starting at 0.
+	Row int64
+
+	// Column is the name of the column of the value, or empty if the row as a
+	// whole is invalid.
+	Column string
+
+	Err error
+}
+
+func (e *CopyRowError) Error() string {
+	if e.Column == "" {
+		return fmt.Sprintf("row %d: %v", e.Row, e.Err)
+	}
+	return fmt.Sprintf("row %d, column %s: %v", e.Row, e.Column, e.Err)
+}
+
+func (e *CopyRowError) Unwrap() error {
+	return e.Err
+}
+
+// CopyColumn describes a column of COPY data.
+type CopyColumn struct {
+	Name string
+
+	// OID is the OID of the column's type.
+	OID uint32
+
+	// TypeModifier is the type modifier of the column, such as the precision
+	// and scale of numeric(p,s), or -1.
+	TypeModifier int32
+}
+
+// CopyColumns returns the columns columnNames of tableName as described by
+// the server.
+func CopyColumns(ctx context.Context, conn *pgx.Conn, tableName pgx.Identifier, columnNames []string) ([]CopyColumn, error) {
+	sd, err := conn.PgConn().Prepare(ctx, "", fmt.Sprintf("select %s from %s", quoteColumnNames(columnNames), tableName.Sanitize()), nil)
+	if err != nil {
+		return nil, fmt.Errorf("statement description failed: %w", err)
+	}
+
+	columns := make([]CopyColumn, len(columnNames))
+	for i, f := range sd.Fields {
+		columns[i] = CopyColumn{Name: columnNames[i], OID: f.DataTypeOID, TypeModifier: f.TypeModifier}
+	}
+
+	return columns, nil
+}
+
+func quoteColumnNames(columnNames []string) string {
+	quoted := make([]string, len(columnNames))
+	for i, name := range columnNames {
+		quoted[i] = pgx.Identifier{name}.Sanitize()
+	}
+	return strings.Join(quoted, ", ")
+}
+
+// copyEncoder encodes rows as tuples of the binary COPY format.
+type copyEncoder struct {
+	m       *pgtype.Map
+	columns []CopyColumn
+	row     int64
+}
+
+// copyBinarySignature starts the header of the binary COPY format, which
+// continues with 32 bit flags and header extension length fields.
+const copyBinarySignature = "PGCOPY\n\377\r\n\000"
+
+func appendCopyHeader(buf []byte) []byte {
+	buf = append(buf, copyBinarySignature...)
+	buf = binary.BigEndian.AppendUint32(buf, 0)
+	return binary.BigEndian.AppendUint32(buf, 0)
+}
+
+func appendCopyTrailer(buf []byte) []byte {
+	return binary.BigEndian.AppendUint16(buf, 0xffff)
+}
+
+// appendRow appends values as the next tuple. Values of numeric columns that
+// hold a decimal128.Decimal, Decimal or NullDecimal, or a pointer to one, are
+// checked against the column's typmod and encoded directly; other values are
+// encoded with e.m.
+func (e *copyEncoder) appendRow(buf []byte, values []any) ([]byte, error) {
+	row := e.row
+	e.row++
+
+	if len(values) != len(e.columns) {
+		return nil, &CopyRowError{Row: row, Err: fmt.Errorf("expected %d values, got %d values", len(e.columns), len(values))}
+	}
+
+	buf = binary.BigEndian.AppendUint16(buf, uint16(len(values)))
+	for i, value := range values {
+		col := e.columns[i]
+
+		var err error
+		if d, ok := copyNumericValue(value); ok && col.OID == pgtype.NumericOID {
+			buf, err = appendCopyNumeric(buf, d, col.TypeModifier)
+		} else {
+			buf, err = appendCopyValue(buf, e.m, col.OID, value)
+		}
+		if err != nil {
+			return nil, &CopyRowError{Row: row, Column: col.Name, Err: err}
+		}
+	}
+
+	return buf, nil
+}
+
+// copyNumericValue returns the value of a decimal argument.
+func copyNumericValue(value any) (NullDecimal, bool) {
+	switch value := value.(type) {
+	case decimal128.Decimal:
+		return NullDecimal{Decimal: value, Valid: true}, true
+	case Decimal:
+		return NullDecimal{Decimal: decimal128.Decimal(value), Valid: true}, true
+	case NullDecimal:
+		return value, true
+	case *decimal128.Decimal:
+		if value == nil {
+			return NullDecimal{}, true
+		}
+		return NullDecimal{Decimal: *value, Valid: true}, true
+	case *Decimal:
+		if value == nil {
+			return NullDecimal{}, true
+		}
+		return NullDecimal{Decimal: decimal128.Decimal(*value), Valid: true}, true
+	case *NullDecimal:
+		if value == nil {
+			return NullDecimal{}, true
+		}
+		return *value, true
+	}
+
+	return NullDecimal{}, false
+}
+
+func appendCopyNumeric(buf []byte, d NullDecimal, typmod int32) ([]byte, error) {
+	if !d.Valid {
+		return binary.BigEndian.AppendUint32(buf, 0xffffffff), nil
+	}
+
+	dd, err := roundNumericTypmod(d.Decimal, typmod)
+	if err != nil {
+		return nil, err
+	}
+
+	sp := len(buf)
+	buf = binary.BigEndian.AppendUint32(buf, 0)
+	buf = AppendNumericSend(buf, dd)
+	binary.BigEndian.PutUint32(buf[sp:], uint32(len(buf)-sp-4))
+
+	return buf, nil
+}
+
+func appendCopyValue(buf []byte, m *pgtype.Map, oid uint32, value any) ([]byte, error) {
+	sp := len(buf)
+	buf = binary.BigEndian.AppendUint32(buf, 0xffffffff)
+
+	argBuf, err := m.Encode(oid, pgtype.BinaryFormatCode, value, buf)
+	if err != nil {
+		return nil, err
+	}
+	if argBuf == nil {
+		return buf, nil
+	}
+
+	binary.BigEndian.PutUint32(argBuf[sp:], uint32(len(argBuf)-sp-4))
+	return argBuf, nil
+}
+
+// CopyFrom copies the rows of rowSrc into the columns columnNames of
+// tableName with the binary COPY protocol, like pgx.Conn.CopyFrom, and
+// returns the number of rows copied.
+//
+// In numeric columns, decimal128.Decimal, Decimal and NullDecimal values and
+// pointers to them are encoded straight from their decimal128 form instead of
+// through NumericValue and a big.Int per value. They are checked against the
+// column's numeric(p,s) first, as by CheckNumericTypmod, and values with more
+// fraction digits than its scale are rounded to it. Other values are
+// encoded with the connection's type map. Invalid rows abort the copy with a
+// *CopyRowError giving the index of the row.
+func CopyFrom(ctx context.Context, conn *pgx.Conn, tableName pgx.Identifier, columnNames []string, rowSrc pgx.CopyFromSource) (int64, error) {
+	columns, err := CopyColumns(ctx, conn, tableName, columnNames)
+	if err != nil {
+		return 0, err
+	}
+	enc := &copyEncoder{m: conn.TypeMap(), columns: columns}
+
+	r, w := io.Pipe()
+	doneChan := make(chan struct{})
+	var encErr error
+
+	go func() {
+		defer close(doneChan)
+
+		buf := appendCopyHeader(make([]byte, 0, copySendBufSize))
+		for {
+			more, newBuf, err := enc.appendRows(buf, rowSrc)
+			if err == nil {
+				err = rowSrc.Err()
+			}
+			if err != nil {
+				encErr = err
+				w.CloseWithError(err)
+				return
+			}
+
+			if !more {
+				newBuf = appendCopyTrailer(newBuf)
+			}
+			if _, err := w.Write(newBuf); err != nil {
+				w.Close()
+				return
+			}
+			if !more {
+				break
+			}
+
+			buf = newBuf[:0]
+		}
+
+		w.Close()
+	}()
+
+	sql := fmt.Sprintf("copy %s ( %s ) from stdin binary", tableName.Sanitize(), quoteColumnNames(columnNames))
+	commandTag, err := conn.PgConn().CopyFrom(ctx, r, sql)
+
+	r.Close()
+	<-doneChan
+
+	// The server only sees the message of an encoding error.
+	if encErr != nil {
+		return 0, encErr
+	}
+
+	return commandTag.RowsAffected(), err
+}
+
+// copySendBufSize is the size of the chunks of copy data sent to the server,
+// which fits a message of 64 kB.
+const copySendBufSize = 65536 - 5
+
+// appendRows appends rows of rowSrc until buf is about to exceed
+// copySendBufSize, and reports whether there may be more rows.
+func (e *copyEncoder) appendRows(buf []byte, rowSrc pgx.CopyFromSource) (bool, []byte, error) {
+	largestRowLen := 0
+
+	for rowSrc.Next() {
+		values, err := rowSrc.Values()
+		if err != nil {
+			return false, nil, err
+		}
+
+		start := len(buf)
+		buf, err = e.appendRow(buf, values)
+		if err != nil {
+			return false, nil, err
+		}
+
+		largestRowLen = max(largestRowLen, len(buf)-start)
+		if len(buf) > copySendBufSize-largestRowLen {
+			return true, buf, nil
+		}
+	}
+
+	return false, buf, nil
+}
@@ -0,0 +1,129 @@
+package decimal_test
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ingothierack/decimal128"
+	pgxdecimal "github.com/ingothierack/pgx-woodsbury-decimal128"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+)
+
+var numericSendTestValues = []string{
+	"0", "1", "-1", "1.50", "-0.001", "12345.6789", "123456789.123456789", "10000", "1e20", "-1.5e-20",
+	"9999999999999999999999999999999999", "1234567890123456789012345678901234e-40",
+	"9999999999999999999999999999999999e100", "1e-6176", "NaN", "Infinity", "-Infinity",
+}
+
+func TestAppendNumericSend(t *testing.T) {
+	m := pgtype.NewMap()
+
+	for _, s := range numericSendTestValues {
+		d := decimal128.MustParse(s)
+
+		var num pgtype.Numeric
+		require.NoError(t, m.Scan(pgtype.NumericOID, pgtype.BinaryFormatCode, pgxdecimal.AppendNumericSend(nil, d), &num), s)
+
+		expected, err := m.Encode(pgtype.NumericOID, pgtype.TextFormatCode, num, nil)
+		require.NoError(t, err)
+		require.Equal(t, pgxdecimal.NumericOut(d), string(expected), s)
+	}
+
+	require.Equal(t, []byte{0xff, 0, 2, 0, 0, 0, 0, 0, 2, 0, 1, 0x13, 0x88}, pgxdecimal.AppendNumericSend([]byte{0xff}, decimal128.MustParse("1.50")))
+}
+
+func TestNumericTypmod(t *testing.T) {
+	typmod, err := pgxdecimal.NumericTypmod(10, 2)
+	require.NoError(t, err)
+	require.Equal(t, int32(10<<16|2+4), typmod)
+
+	_, err = pgxdecimal.NumericTypmod(0, 0)
+	require.Error(t, err)
+	_, err = pgxdecimal.NumericTypmod(10, 1001)
+	require.Error(t, err)
+}
+
+func TestCheckNumericTypmod(t *testing.T) {
+	tests := []struct {
+		value     string
+		precision int
+		scale     int
+		ok        bool
+	}{
+		{"99999999.99", 10, 2, true},
+		{"100000000", 10, 2, false},
+		{"-99999999.994", 10, 2, true},
+		{"99999999.995", 10, 2, false},
+		{"0.001", 3, 3, true},
+		{"1", 3, 3, false},
+		{"0", 1, 0, true},
+		{"1e20", 30, 0, true},
+		{"1e20", 20, 0, false},
+		{"123450", 4, -2, true},
+		{"1234500", 4, -2, false},
+		{"0.0123", 2, 3, true},
+		{"0.123", 2, 3, false},
+		{"NaN", 5, 2, true},
+		{"Infinity", 5, 2, false},
+	}
+
+	for _, tt := range tests {
+		typmod, err := pgxdecimal.NumericTypmod(tt.precision, tt.scale)
+		require.NoError(t, err)
+
+		err = pgxdecimal.CheckNumericTypmod(decimal128.MustParse(tt.value), typmod)
+		if tt.ok {
+			require.NoError(t, err, "%s numeric(%d,%d)", tt.value, tt.precision, tt.scale)
+		} else {
+			require.EqualError(t, err, "numeric field overflow", "%s numeric(%d,%d)", tt.value, tt.precision, tt.scale)
+		}
+	}
+
+	require.NoError(t, pgxdecimal.CheckNumericTypmod(decimal128.Inf(1), -1))
+}
+
+func TestCopyFromMatchServer(t *testing.T) {
+	defaultConnTestRunner.RunTest(context.Background(), t, func(ctx context.Context, t testing.TB, conn *pgx.Conn) {
+		_, err := conn.Exec(ctx, "create temporary table copy_decimals (id int8, amount numeric(10,2), free numeric)")
+		require.NoError(t, err)
+
+		pointer := decimal128.MustParse("-0.5")
+		rows := [][]any{
+			{int64(1), decimal128.MustParse("12.345"), pgxdecimal.Decimal(decimal128.MustParse("1e20"))},
+			{int64(2), pgxdecimal.NullDecimal{}, decimal128.NaN()},
+			{int64(3), &pointer, decimal128.Inf(-1)},
+			{int64(4), pgtype.Numeric{Int: big.NewInt(725), Exp: -2, Valid: true}, nil},
+		}
+
+		n, err := pgxdecimal.CopyFrom(ctx, conn, pgx.Identifier{"copy_decimals"}, []string{"id", "amount", "free"}, pgx.CopyFromRows(rows))
+		require.NoError(t, err)
+		require.Equal(t, int64(len(rows)), n)
+
+		result, err := conn.Query(ctx, "select coalesce(amount::text, 'NULL') || ' ' || coalesce(free::text, 'NULL') from copy_decimals order by id")
+		require.NoError(t, err)
+		values, err := pgx.CollectRows(result, pgx.RowTo[string])
+		require.NoError(t, err)
+		require.Equal(t, []string{"12.35 100000000000000000000", "NULL NaN", "-0.50 -Infinity", "7.25 NULL"}, values)
+
+		rows = [][]any{
+			{int64(5), decimal128.FromInt64(1), nil},
+			{int64(6), decimal128.FromInt64(2), nil},
+			{int64(7), decimal128.MustParse("123456789"), nil},
+		}
+		_, err = pgxdecimal.CopyFrom(ctx, conn, pgx.Identifier{"copy_decimals"}, []string{"id", "amount", "free"}, pgx.CopyFromRows(rows))
+
+		var rowErr *pgxdecimal.CopyRowError
+		require.True(t, errors.As(err, &rowErr), "%v", err)
+		require.Equal(t, int64(2), rowErr.Row)
+		require.Equal(t, "amount", rowErr.Column)
+		require.EqualError(t, rowErr.Err, "numeric field overflow")
+
+		var count int
+		require.NoError(t, conn.QueryRow(ctx, "select count(*) from copy_decimals").Scan(&count))
+		require.Equal(t, 4, count)
+	})
+}
@@ -0,0 +1,620 @@
+package decimal
+
+import (
+	"bufio"
+	"bytes"
+	"cmp"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+var (
+	errInvalidCopySignature = errors.New("invalid COPY binary signature")
+	errCopyWithOIDs         = errors.New("COPY binary data with OIDs is not supported")
+	errUnexpectedCopyEOF    = errors.New("unexpected end of COPY data")
+)
+
+// CopyFormat is the format of COPY data.
+type CopyFormat int
+
+const (
+	CopyText CopyFormat = iota
+	CopyCSV
+	CopyBinary
+)
+
+// CopyOptions are the options of COPY that determine how its text and csv
+// output is read. They have no effect on the binary format.
+type CopyOptions struct {
+	Format CopyFormat
+
+	// Delimiter separates columns. The default is a tab in text and a comma
+	// in csv format.
+	Delimiter byte
+
+	// Null is the string of NULL values, or nil for the default: \N in text
+	// and an unquoted empty string in csv format.
+	Null *string
+
+	// Quote and Escape are the quote and escape characters of the csv
+	// format. Quote defaults to a double quote and Escape to Quote.
+	Quote  byte
+	Escape byte
+
+	// Header is set if the first line holds column names.
+	Header bool
+}
+
+// CopyDecoder reads rows of COPY ... TO output in binary, text or csv format
+// and converts their fields with a pgtype.Map, so numeric columns decode to
+// decimal128.Decimal, Decimal and NullDecimal with the same rules as query
+// results when the map has Register applied.
+type CopyDecoder struct {
+	r       *bufio.Reader
+	m       *pgtype.Map
+	columns []CopyColumn
+	opts    CopyOptions
+	null    []byte
+
+	started bool
+	done    bool
+	row     int64
+	err     error
+
+	line []byte
+
+	// buf holds the fields of the current row. It is never nil, so that
+	// empty fields are not taken for NULL.
+	buf     []byte
+	lengths []int32
+	fields  [][]byte
+}
+
+// NewCopyDecoder returns a decoder of the COPY data in r, which has columns
+// columns in the format given by opts.
+func NewCopyDecoder(r io.Reader, m *pgtype.Map, columns []CopyColumn, opts CopyOptions) *CopyDecoder {
+	d := &CopyDecoder{
+		r:       bufio.NewReaderSize(r, 65536),
+		m:       m,
+		columns: columns,
+		opts:    opts,
+		buf:     make([]byte, 0, 256),
+		fields:  make([][]byte, 0, len(columns)),
+	}
+
+	switch opts.Format {
+	case CopyText:
+		if d.opts.Delimiter == 0 {
+			d.opts.Delimiter = '\t'
+		}
+		d.null = []byte(`\N`)
+	case CopyCSV:
+		if d.opts.Delimiter == 0 {
+			d.opts.Delimiter = ','
+		}
+		if d.opts.Quote == 0 {
+			d.opts.Quote = '"'
+		}
+		if d.opts.Escape == 0 {
+			d.opts.Escape = d.opts.Quote
+		}
+		d.null = []byte{}
+	}
+	if opts.Null != nil {
+		d.null = []byte(*opts.Null)
+	}
+
+	return d
+}
+
+// Next reads the next row and reports whether there is one. It returns false
+// at the end of the data or on error, which Err returns.
+func (d *CopyDecoder) Next() bool {
+	if d.done {
+		return false
+	}
+
+	if !d.started {
+		d.started = true
+		if d.err = d.readHeader(); d.err != nil {
+			d.done = true
+			return false
+		}
+	}
+
+	var ok bool
+	switch d.opts.Format {
+	case CopyBinary:
+		ok, d.err = d.readBinaryRow()
+	case CopyCSV:
+		ok, d.err = d.readCSVRow()
+	default:
+		ok, d.err = d.readTextRow()
+	}
+
+	if d.err == nil && ok && len(d.fields) != len(d.columns) {
+		d.err = fmt.Errorf("expected %d values, got %d values", len(d.columns), len(d.fields))
+	}
+	if d.err != nil {
+		d.err = &CopyRowError{Row: d.row, Err: d.err}
+		ok = false
+	}
+	if !ok {
+		d.done = true
+		return false
+	}
+
+	d.row++
+	return true
+}
+
+// Err returns the error that ended Next, if any.
+func (d *CopyDecoder) Err() error {
+	return d.err
+}
+
+// RawValues returns the fields of the current row, with text and csv fields
+// unescaped, and nil for NULL. The slices are only valid until the next call
+// to Next.
+func (d *CopyDecoder) RawValues() [][]byte {
+	return d.fields
+}
+
+// formatCode returns the format code of the fields.
+func (d *CopyDecoder) formatCode() int16 {
+	if d.opts.Format == CopyBinary {
+		return pgtype.BinaryFormatCode
+	}
+	return pgtype.TextFormatCode
+}
+
+// Scan scans the fields of the current row into dest, like pgx.Rows.Scan. A
+// nil dest skips its column.
+func (d *CopyDecoder) Scan(dest ...any) error {
+	if len(dest) != len(d.fields) {
+		return fmt.Errorf("number of field descriptions must equal number of destinations, got %d and %d", len(d.fields), len(dest))
+	}
+
+	for i, dst := range dest {
+		if dst == nil {
+			continue
+		}
+
+		if err := d.m.Scan(d.columns[i].OID, d.formatCode(), d.fields[i], dst); err != nil {
+			return &CopyRowError{Row: d.row - 1, Column: d.columns[i].Name, Err: err}
+		}
+	}
+
+	return nil
+}
+
+// Values returns the fields of the current row decoded by the codecs of the
+// columns' types, like pgx.Rows.Values. Fields of unknown types are returned
+// as a string in text and csv and as a []byte in binary format.
+func (d *CopyDecoder) Values() ([]any, error) {
+	values := make([]any, len(d.fields))
+	for i, src := range d.fields {
+		if src == nil {
+			continue
+		}
+
+		col := d.columns[i]
+		t, ok := d.m.TypeForOID(col.OID)
+		if !ok {
+			if d.opts.Format == CopyBinary {
+				values[i] = bytes.Clone(src)
+			} else {
+				values[i] = string(src)
+			}
+			continue
+		}
+
+		v, err := t.Codec.DecodeValue(d.m, col.OID, d.formatCode(), src)
+		if err != nil {
+			return nil, &CopyRowError{Row: d.row - 1, Column: col.Name, Err: err}
+		}
+		values[i] = v
+	}
+
+	return values, nil
+}
+
+func (d *CopyDecoder) readHeader() error {
+	if d.opts.Format == CopyBinary {
+		return d.readBinaryHeader()
+	}
+
+	if d.opts.Header {
+		_, err := d.readLine()
+		if err == io.EOF {
+			return nil
+		}
+		return err
+	}
+
+	return nil
+}
+
+func (d *CopyDecoder) readBinaryHeader() error {
+	var header [len(copyBinarySignature) + 8]byte
+	if _, err := io.ReadFull(d.r, header[:]); err != nil {
+		return copyReadError(err)
+	}
+	if string(header[:len(copyBinarySignature)]) != copyBinarySignature {
+		return errInvalidCopySignature
+	}
+
+	flags := binary.BigEndian.Uint32(header[len(copyBinarySignature):])
+	if flags&(1<<16) != 0 {
+		return errCopyWithOIDs
+	}
+
+	extLen := binary.BigEndian.Uint32(header[len(copyBinarySignature)+4:])
+	if _, err := d.r.Discard(int(extLen)); err != nil {
+		return copyReadError(err)
+	}
+
+	return nil
+}
+
+// copyReadChunkSize is the most that readBinaryRow reads of a field at once.
+const copyReadChunkSize = 1 << 20
+
+func (d *CopyDecoder) readBinaryRow() (bool, error) {
+	var word [4]byte
+	if _, err := io.ReadFull(d.r, word[:2]); err != nil {
+		// The trailer is optional when the data ends with the stream.
+		if err == io.EOF {
+			return false, nil
+		}
+		return false, copyReadError(err)
+	}
+
+	n := int16(binary.BigEndian.Uint16(word[:2]))
+	if n == -1 {
+		return false, nil
+	}
+	if n < 0 {
+		return false, fmt.Errorf("invalid COPY field count %d", n)
+	}
+
+	d.buf = d.buf[:0]
+	d.lengths = slices.Grow(d.lengths[:0], int(n))[:n]
+	lengths := d.lengths
+	for i := range lengths {
+		if _, err := io.ReadFull(d.r, word[:]); err != nil {
+			return false, copyReadError(err)
+		}
+
+		lengths[i] = int32(binary.BigEndian.Uint32(word[:]))
+		if lengths[i] == -1 {
+			continue
+		}
+		if lengths[i] < 0 {
+			return false, fmt.Errorf("invalid COPY field length %d", lengths[i])
+		}
+
+		// Read large fields in chunks, so that a corrupt length fails at the
+		// end of the data instead of allocating up to 2 GB up front.
+		for remaining := int(lengths[i]); remaining > 0; {
+			n := min(remaining, copyReadChunkSize)
+			start := len(d.buf)
+			d.buf = slices.Grow(d.buf, n)[:start+n]
+			if _, err := io.ReadFull(d.r, d.buf[start:]); err != nil {
+				return false, copyReadError(err)
+			}
+			remaining -= n
+		}
+	}
+
+	d.fields = d.fields[:0]
+	offset := 0
+	for _, length := range lengths {
+		if length == -1 {
+			d.fields = append(d.fields, nil)
+			continue
+		}
+		d.fields = append(d.fields, d.buf[offset:offset+int(length):offset+int(length)])
+		offset += int(length)
+	}
+
+	return true, nil
+}
+
+// copyReadError returns errUnexpectedCopyEOF for the end of r, and other read
+// errors as they are.
+func copyReadError(err error) error {
+	if err == io.EOF || err == io.ErrUnexpectedEOF {
+		return errUnexpectedCopyEOF
+	}
+	return err
+}
+
+// readLine returns the next line without its line terminator, or io.EOF.
+func (d *CopyDecoder) readLine() ([]byte, error) {
+	d.line = d.line[:0]
+	for {
+		b, err := d.r.ReadSlice('\n')
+		d.line = append(d.line, b...)
+
+		switch err {
+		case nil:
+			d.line = bytes.TrimSuffix(d.line[:len(d.line)-1], []byte{'\r'})
+			return d.line, nil
+		case bufio.ErrBufferFull:
+			continue
+		case io.EOF:
+			if len(d.line) == 0 {
+				return nil, io.EOF
+			}
+			return d.line, nil
+		default:
+			return nil, err
+		}
+	}
+}
+
+// fieldSpan is a field of a row in d.buf, with start -1 for NULL.
+type fieldSpan struct{ start, end int }
+
+func (d *CopyDecoder) setFields(spans []fieldSpan) {
+	d.fields = d.fields[:0]
+	for _, s := range spans {
+		if s.start < 0 {
+			d.fields = append(d.fields, nil)
+		} else {
+			d.fields = append(d.fields, d.buf[s.start:s.end:s.end])
+		}
+	}
+}
+
+func (d *CopyDecoder) readTextRow() (bool, error) {
+	line, err := d.readLine()
+	if err == io.EOF {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	if string(line) == `\.` {
+		return false, nil
+	}
+
+	d.buf = d.buf[:0]
+	spans := make([]fieldSpan, 0, len(d.columns))
+	for fieldStart := 0; ; {
+		fieldEnd := fieldStart
+		for fieldEnd < len(line) && line[fieldEnd] != d.opts.Delimiter {
+			if line[fieldEnd] == '\\' && fieldEnd+1 < len(line) {
+				fieldEnd++
+			}
+			fieldEnd++
+		}
+
+		raw := line[fieldStart:fieldEnd]
+		if bytes.Equal(raw, d.null) {
+			spans = append(spans, fieldSpan{-1, -1})
+		} else {
+			start := len(d.buf)
+			d.buf = appendUnescapedCopyText(d.buf, raw)
+			spans = append(spans, fieldSpan{start, len(d.buf)})
+		}
+
+		if fieldEnd == len(line) {
+			break
+		}
+		fieldStart = fieldEnd + 1
+	}
+
+	d.setFields(spans)
+	return true, nil
+}
+
+// appendUnescapedCopyText appends the text format field raw with its
+// backslash escapes decoded to buf.
+func appendUnescapedCopyText(buf, raw []byte) []byte {
+	for i := 0; i < len(raw); i++ {
+		c := raw[i]
+		if c != '\\' || i+1 == len(raw) {
+			buf = append(buf, c)
+			continue
+		}
+
+		i++
+		c = raw[i]
+		switch c {
+		case 'b':
+			buf = append(buf, '\b')
+		case 'f':
+			buf = append(buf, '\f')
+		case 'n':
+			buf = append(buf, '\n')
+		case 'r':
+			buf = append(buf, '\r')
+		case 't':
+			buf = append(buf, '\t')
+		case 'v':
+			buf = append(buf, '\v')
+		case 'x':
+			v, n := 0, 0
+			for ; n < 2 && i+1 < len(raw) && isHexDigit(raw[i+1]); n++ {
+				i++
+				v = v*16 + hexDigitValue(raw[i])
+			}
+			if n == 0 {
+				buf = append(buf, 'x')
+			} else {
+				buf = append(buf, byte(v))
+			}
+		case '0', '1', '2', '3', '4', '5', '6', '7':
+			v := int(c - '0')
+			for n := 1; n < 3 && i+1 < len(raw) && raw[i+1] >= '0' && raw[i+1] <= '7'; n++ {
+				i++
+				v = v*8 + int(raw[i]-'0')
+			}
+			buf = append(buf, byte(v))
+		default:
+			buf = append(buf, c)
+		}
+	}
+
+	return buf
+}
+
+func isHexDigit(c byte) bool {
+	return c >= '0' && c <= '9' || c >= 'a' && c <= 'f' || c >= 'A' && c <= 'F'
+}
+
+func hexDigitValue(c byte) int {
+	switch {
+	case c >= 'a':
+		return int(c-'a') + 10
+	case c >= 'A':
+		return int(c-'A') + 10
+	}
+	return int(c - '0')
+}
+
+// readCSVRow reads a csv row like CopyReadAttributesCSV. A field is NULL if
+// it matches the null string and has no quotes; quoted fields may span
+// lines.
+func (d *CopyDecoder) readCSVRow() (bool, error) {
+	line, err := d.readLine()
+	if err == io.EOF {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	if string(line) == `\.` {
+		return false, nil
+	}
+
+	delim, quote, escape := d.opts.Delimiter, d.opts.Quote, d.opts.Escape
+	d.buf = d.buf[:0]
+	spans := make([]fieldSpan, 0, len(d.columns))
+
+	i := 0
+	for {
+		start := len(d.buf)
+		sawQuote, inQuote := false, false
+
+		for {
+			if i == len(line) {
+				if !inQuote {
+					break
+				}
+
+				// The quoted value continues on the next line.
+				next, err := d.readLine()
+				if err == io.EOF {
+					return false, errors.New("unterminated CSV quoted field")
+				}
+				if err != nil {
+					return false, err
+				}
+				d.buf = append(d.buf, '\n')
+				line, i = next, 0
+				continue
+			}
+
+			c := line[i]
+			i++
+
+			if !inQuote {
+				if c == delim {
+					i--
+					break
+				}
+				if c == quote {
+					sawQuote, inQuote = true, true
+					continue
+				}
+				d.buf = append(d.buf, c)
+				continue
+			}
+
+			if c == escape && i < len(line) && (line[i] == escape || line[i] == quote) {
+				d.buf = append(d.buf, line[i])
+				i++
+				continue
+			}
+			if c == quote {
+				inQuote = false
+				continue
+			}
+			d.buf = append(d.buf, c)
+		}
+
+		if !sawQuote && bytes.Equal(d.buf[start:], d.null) {
+			d.buf = d.buf[:start]
+			spans = append(spans, fieldSpan{-1, -1})
+		} else {
+			spans = append(spans, fieldSpan{start, len(d.buf)})
+		}
+
+		if i == len(line) {
+			break
+		}
+		i++ // delimiter
+	}
+
+	d.setFields(spans)
+	return true, nil
+}
+
+// CopyTo runs sql, a COPY ... TO STDOUT statement, on conn and calls fn with
+// a decoder positioned at each row of its output, which has columns columns
+// in the format given by opts. It returns the number of rows copied. If fn
+// returns an error or the output cannot be decoded, the copy is aborted, which
+// closes the connection as with pgconn.PgConn.CopyTo and a failing writer.
+func CopyTo(ctx context.Context, conn *pgx.Conn, sql string, columns []CopyColumn, opts CopyOptions, fn func(*CopyDecoder) error) (int64, error) {
+	r, w := io.Pipe()
+	doneChan := make(chan struct{})
+
+	var commandTag int64
+	var copyErr error
+	go func() {
+		defer close(doneChan)
+
+		tag, err := conn.PgConn().CopyTo(ctx, w, sql)
+		commandTag, copyErr = tag.RowsAffected(), err
+		w.CloseWithError(err)
+	}()
+
+	dec := NewCopyDecoder(r, conn.TypeMap(), columns, opts)
+
+	var err error
+	for dec.Next() {
+		if err = fn(dec); err != nil {
+			break
+		}
+	}
+	if err == nil {
+		err = dec.Err()
+	}
+	if err == nil {
+		// Read anything after the end of the data, such as the server's error.
+		_, err = io.Copy(io.Discard, r)
+	}
+
+	// Errors closing r are returned by CopyTo, as are errors of the server.
+	r.CloseWithError(cmp.Or(err, io.ErrClosedPipe))
+	<-doneChan
+
+	if copyErr != nil {
+		return 0, copyErr
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	return commandTag, nil
+}
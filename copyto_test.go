@@ -0,0 +1,241 @@
+package decimal_test
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/ingothierack/decimal128"
+	pgxdecimal "github.com/ingothierack/pgx-woodsbury-decimal128"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+)
+
+var copyToTestColumns = []pgxdecimal.CopyColumn{
+	{Name: "id", OID: pgtype.Int8OID, TypeModifier: -1},
+	{Name: "amount", OID: pgtype.NumericOID, TypeModifier: -1},
+	{Name: "note", OID: pgtype.TextOID, TypeModifier: -1},
+}
+
+func newCopyTestMap() *pgtype.Map {
+	m := pgtype.NewMap()
+	pgxdecimal.Register(m)
+	return m
+}
+
+type copyTestRow struct {
+	id     int64
+	amount pgxdecimal.NullDecimal
+	note   *string
+}
+
+func scanCopyRows(t *testing.T, dec *pgxdecimal.CopyDecoder) []copyTestRow {
+	var rows []copyTestRow
+	for dec.Next() {
+		var row copyTestRow
+		require.NoError(t, dec.Scan(&row.id, &row.amount, &row.note))
+		rows = append(rows, row)
+	}
+	require.NoError(t, dec.Err())
+	return rows
+}
+
+func requireCopyTestRows(t *testing.T, rows []copyTestRow) {
+	require.Len(t, rows, 3)
+
+	require.Equal(t, int64(1), rows[0].id)
+	require.True(t, rows[0].amount.Valid)
+	require.Equal(t, "12.50", pgxdecimal.NumericOut(rows[0].amount.Decimal))
+	require.Equal(t, "a\tb\nc", *rows[0].note)
+
+	require.Equal(t, int64(2), rows[1].id)
+	require.False(t, rows[1].amount.Valid)
+	require.Nil(t, rows[1].note)
+
+	require.Equal(t, "-0.000001", pgxdecimal.NumericOut(rows[2].amount.Decimal))
+	require.Equal(t, "", *rows[2].note)
+}
+
+func TestCopyDecoderText(t *testing.T) {
+	data := "1\t12.50\ta\\tb\\nc\n2\t\\N\t\\N\n3\t-0.000001\t\n"
+	dec := pgxdecimal.NewCopyDecoder(strings.NewReader(data), newCopyTestMap(), copyToTestColumns, pgxdecimal.CopyOptions{})
+	requireCopyTestRows(t, scanCopyRows(t, dec))
+}
+
+func TestCopyDecoderCSV(t *testing.T) {
+	data := "id,amount,note\n1,12.50,\"a\tb\nc\"\n2,,\n3,-0.000001,\"\"\n"
+	dec := pgxdecimal.NewCopyDecoder(strings.NewReader(data), newCopyTestMap(), copyToTestColumns, pgxdecimal.CopyOptions{Format: pgxdecimal.CopyCSV, Header: true})
+	requireCopyTestRows(t, scanCopyRows(t, dec))
+
+	null := "NULL"
+	data = "1;'12.50';'a\tb\nc'\n2;NULL;NULL\n3;-0.000001;\n"
+	dec = pgxdecimal.NewCopyDecoder(strings.NewReader(data), newCopyTestMap(), copyToTestColumns, pgxdecimal.CopyOptions{
+		Format: pgxdecimal.CopyCSV, Delimiter: ';', Null: &null, Quote: '\'',
+	})
+	requireCopyTestRows(t, scanCopyRows(t, dec))
+
+	dec = pgxdecimal.NewCopyDecoder(strings.NewReader(`1,2,"it\"s"`+"\n"), newCopyTestMap(), copyToTestColumns, pgxdecimal.CopyOptions{Format: pgxdecimal.CopyCSV, Escape: '\\'})
+	require.True(t, dec.Next())
+	require.Equal(t, `it"s`, string(dec.RawValues()[2]))
+}
+
+func appendCopyTestField(buf []byte, field []byte) []byte {
+	if field == nil {
+		return binary.BigEndian.AppendUint32(buf, 0xffffffff)
+	}
+	buf = binary.BigEndian.AppendUint32(buf, uint32(len(field)))
+	return append(buf, field...)
+}
+
+func TestCopyDecoderBinary(t *testing.T) {
+	buf := []byte("PGCOPY\n\377\r\n\000\000\000\000\000\000\000\000\000")
+
+	rows := []struct {
+		id     int64
+		amount *decimal128.Decimal
+		note   []byte
+	}{
+		{1, ptr(decimal128.MustParse("12.50")), []byte("a\tb\nc")},
+		{2, nil, nil},
+		{3, ptr(decimal128.MustParse("-0.000001")), []byte{}},
+	}
+	for _, row := range rows {
+		buf = binary.BigEndian.AppendUint16(buf, 3)
+		buf = appendCopyTestField(buf, binary.BigEndian.AppendUint64(nil, uint64(row.id)))
+		if row.amount == nil {
+			buf = appendCopyTestField(buf, nil)
+		} else {
+			buf = appendCopyTestField(buf, pgxdecimal.AppendNumericSend([]byte{}, *row.amount))
+		}
+		buf = appendCopyTestField(buf, row.note)
+	}
+	buf = binary.BigEndian.AppendUint16(buf, 0xffff)
+
+	m := newCopyTestMap()
+	opts := pgxdecimal.CopyOptions{Format: pgxdecimal.CopyBinary}
+	requireCopyTestRows(t, scanCopyRows(t, pgxdecimal.NewCopyDecoder(strings.NewReader(string(buf)), m, copyToTestColumns, opts)))
+
+	dec := pgxdecimal.NewCopyDecoder(strings.NewReader(string(buf)), m, copyToTestColumns, opts)
+	require.True(t, dec.Next())
+	values, err := dec.Values()
+	require.NoError(t, err)
+	require.Equal(t, int64(1), values[0])
+	require.Equal(t, "12.5", values[1].(decimal128.Decimal).String())
+	require.Equal(t, "a\tb\nc", values[2])
+
+	dec = pgxdecimal.NewCopyDecoder(strings.NewReader(string(buf[:len(buf)-10])), m, copyToTestColumns, opts)
+	for dec.Next() {
+	}
+	var rowErr *pgxdecimal.CopyRowError
+	require.True(t, errors.As(dec.Err(), &rowErr))
+	require.Equal(t, int64(2), rowErr.Row)
+
+	dec = pgxdecimal.NewCopyDecoder(strings.NewReader("PGCOPY\n"), m, copyToTestColumns, opts)
+	require.False(t, dec.Next())
+	require.Error(t, dec.Err())
+}
+
+func TestCopyDecoderEmptyFields(t *testing.T) {
+	columns := []pgxdecimal.CopyColumn{{Name: "note", OID: pgtype.TextOID, TypeModifier: -1}}
+
+	scanNotes := func(dec *pgxdecimal.CopyDecoder) []pgtype.Text {
+		var notes []pgtype.Text
+		for dec.Next() {
+			var note pgtype.Text
+			require.NoError(t, dec.Scan(&note))
+			notes = append(notes, note)
+		}
+		require.NoError(t, dec.Err())
+		return notes
+	}
+	expected := []pgtype.Text{{String: "", Valid: true}, {}, {String: "x", Valid: true}}
+
+	dec := pgxdecimal.NewCopyDecoder(strings.NewReader("\"\"\n\nx\n"), newCopyTestMap(), columns, pgxdecimal.CopyOptions{Format: pgxdecimal.CopyCSV})
+	require.Equal(t, expected, scanNotes(dec))
+
+	buf := []byte("PGCOPY\n\377\r\n\000\000\000\000\000\000\000\000\000")
+	for _, field := range [][]byte{{}, nil, []byte("x")} {
+		buf = binary.BigEndian.AppendUint16(buf, 1)
+		buf = appendCopyTestField(buf, field)
+	}
+	dec = pgxdecimal.NewCopyDecoder(strings.NewReader(string(buf)), newCopyTestMap(), columns, pgxdecimal.CopyOptions{Format: pgxdecimal.CopyBinary})
+	require.Equal(t, expected, scanNotes(dec))
+}
+
+func TestCopyDecoderBinaryFieldLength(t *testing.T) {
+	columns := []pgxdecimal.CopyColumn{{Name: "note", OID: pgtype.TextOID, TypeModifier: -1}}
+	header := []byte("PGCOPY\n\377\r\n\000\000\000\000\000\000\000\000\000")
+
+	for _, length := range []uint32{0xfffffffe, 0x80000000} {
+		buf := binary.BigEndian.AppendUint16(slices.Clone(header), 1)
+		buf = binary.BigEndian.AppendUint32(buf, length)
+		dec := pgxdecimal.NewCopyDecoder(strings.NewReader(string(buf)), newCopyTestMap(), columns, pgxdecimal.CopyOptions{Format: pgxdecimal.CopyBinary})
+		require.False(t, dec.Next())
+		require.ErrorContains(t, dec.Err(), "invalid COPY field length")
+	}
+
+	// A length beyond the end of the data fails when the data ends.
+	buf := binary.BigEndian.AppendUint16(slices.Clone(header), 1)
+	buf = binary.BigEndian.AppendUint32(buf, 0x7fffffff)
+	buf = append(buf, "short"...)
+	dec := pgxdecimal.NewCopyDecoder(strings.NewReader(string(buf)), newCopyTestMap(), columns, pgxdecimal.CopyOptions{Format: pgxdecimal.CopyBinary})
+	require.False(t, dec.Next())
+	require.ErrorContains(t, dec.Err(), "unexpected end of COPY data")
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
+
+func TestCopyDecoderPolicies(t *testing.T) {
+	data := "1\tNaN\tx\n"
+	dec := pgxdecimal.NewCopyDecoder(strings.NewReader(data), newCopyTestMap(), copyToTestColumns, pgxdecimal.CopyOptions{})
+	require.True(t, dec.Next())
+
+	var d pgxdecimal.Decimal
+	err := dec.Scan(nil, &d, nil)
+	var rowErr *pgxdecimal.CopyRowError
+	require.True(t, errors.As(err, &rowErr))
+	require.Equal(t, int64(0), rowErr.Row)
+	require.Equal(t, "amount", rowErr.Column)
+
+	dec = pgxdecimal.NewCopyDecoder(strings.NewReader("1\t2\n"), newCopyTestMap(), copyToTestColumns, pgxdecimal.CopyOptions{})
+	require.False(t, dec.Next())
+	require.Error(t, dec.Err())
+}
+
+func TestCopyToMatchServer(t *testing.T) {
+	defaultConnTestRunner.RunTest(context.Background(), t, func(ctx context.Context, t testing.TB, conn *pgx.Conn) {
+		_, err := conn.Exec(ctx, `create temporary table copy_to_decimals (id int8, amount numeric(12,2), note text);
+			insert into copy_to_decimals values (1, 12.5, e'a\tb\nc'), (2, null, null), (3, -0.000001, '')`)
+		require.NoError(t, err)
+
+		columns, err := pgxdecimal.CopyColumns(ctx, conn, pgx.Identifier{"copy_to_decimals"}, []string{"id", "amount", "note"})
+		require.NoError(t, err)
+
+		for _, format := range []string{"text", "csv", "binary"} {
+			opts := pgxdecimal.CopyOptions{Format: map[string]pgxdecimal.CopyFormat{
+				"text": pgxdecimal.CopyText, "csv": pgxdecimal.CopyCSV, "binary": pgxdecimal.CopyBinary,
+			}[format]}
+
+			var amounts []string
+			n, err := pgxdecimal.CopyTo(ctx, conn, "copy copy_to_decimals to stdout with (format "+format+")", columns, opts,
+				func(dec *pgxdecimal.CopyDecoder) error {
+					var row copyTestRow
+					if err := dec.Scan(&row.id, &row.amount, &row.note); err != nil {
+						return err
+					}
+					text, err := row.amount.MarshalText()
+					amounts = append(amounts, string(text))
+					return err
+				})
+			require.NoError(t, err, format)
+			require.Equal(t, int64(3), n, format)
+			require.Equal(t, []string{"12.50", "NULL", "0.00"}, amounts, format)
+		}
+	})
+}
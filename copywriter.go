@@ -0,0 +1,96 @@
+package decimal
+
+import (
+	"errors"
+	"io"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+var errCopyWriterClosed = errors.New("CopyWriter is closed")
+
+// CopyWriter writes data in the binary COPY format, as read by COPY ... FROM
+// STDIN (FORMAT binary) or from a file, without a database connection. Values
+// are encoded as by CopyFrom for the columns given, so decimal values in
+// numeric columns are checked against their TypeModifier and rounded to its
+// scale as the server's apply_typmod does, so 12.345 is written to a
+// numeric(10,2) column as 12.35. Values with fewer fraction digits keep their
+// own display scale. A file written by a CopyWriter can be read back with
+// NewCopyDecoder and CopyBinary.
+type CopyWriter struct {
+	w    io.Writer
+	enc  copyEncoder
+	buf  []byte
+	rows int64
+	err  error
+}
+
+// NewCopyWriter returns a CopyWriter that writes rows with columns columns to
+// w. m encodes values other than decimals; if it is nil, a new pgtype.Map
+// with Register applied is used. Column type modifiers can be built with
+// NumericTypmod.
+func NewCopyWriter(w io.Writer, m *pgtype.Map, columns []CopyColumn) *CopyWriter {
+	if m == nil {
+		m = pgtype.NewMap()
+		Register(m)
+	}
+
+	return &CopyWriter{
+		w:   w,
+		enc: copyEncoder{m: m, columns: columns},
+		buf: appendCopyHeader(make([]byte, 0, copySendBufSize)),
+	}
+}
+
+// WriteRow writes a row of values, one for each column. A row that cannot be
+// encoded is not written and the *CopyRowError is returned, whose Row counts
+// the calls of WriteRow. Errors writing to w are returned by all later calls.
+func (cw *CopyWriter) WriteRow(values ...any) error {
+	if cw.err != nil {
+		return cw.err
+	}
+
+	buf, err := cw.enc.appendRow(cw.buf, values)
+	if err != nil {
+		return err
+	}
+	cw.buf = buf
+	cw.rows++
+
+	if len(cw.buf) >= copySendBufSize {
+		return cw.flush()
+	}
+
+	return nil
+}
+
+// Rows returns the number of rows written.
+func (cw *CopyWriter) Rows() int64 {
+	return cw.rows
+}
+
+func (cw *CopyWriter) flush() error {
+	if _, err := cw.w.Write(cw.buf); err != nil {
+		cw.err = err
+		return err
+	}
+
+	cw.buf = cw.buf[:0]
+	return nil
+}
+
+// Close writes the file trailer and any buffered rows. It does not close the
+// underlying writer.
+func (cw *CopyWriter) Close() error {
+	if cw.err != nil {
+		return cw.err
+	}
+
+	cw.buf = appendCopyTrailer(cw.buf)
+	err := cw.flush()
+	if err == nil {
+		cw.err = errCopyWriterClosed
+	}
+
+	return err
+}
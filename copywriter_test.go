@@ -0,0 +1,129 @@
+package decimal_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/ingothierack/decimal128"
+	pgxdecimal "github.com/ingothierack/pgx-woodsbury-decimal128"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+)
+
+func copyWriterTestColumns(t testing.TB) []pgxdecimal.CopyColumn {
+	typmod, err := pgxdecimal.NumericTypmod(10, 2)
+	require.NoError(t, err)
+
+	return []pgxdecimal.CopyColumn{
+		{Name: "id", OID: pgtype.Int8OID, TypeModifier: -1},
+		{Name: "amount", OID: pgtype.NumericOID, TypeModifier: typmod},
+		{Name: "note", OID: pgtype.TextOID, TypeModifier: -1},
+	}
+}
+
+func writeCopyTestFile(t testing.TB, columns []pgxdecimal.CopyColumn) []byte {
+	var buf bytes.Buffer
+	cw := pgxdecimal.NewCopyWriter(&buf, nil, columns)
+
+	require.NoError(t, cw.WriteRow(int64(1), decimal128.MustParse("12.50"), "a\tb\nc"))
+	require.NoError(t, cw.WriteRow(int64(2), pgxdecimal.NullDecimal{}, nil))
+
+	err := cw.WriteRow(int64(99), decimal128.MustParse("123456789"), "too large")
+	var rowErr *pgxdecimal.CopyRowError
+	require.True(t, errors.As(err, &rowErr))
+	require.Equal(t, int64(2), rowErr.Row)
+	require.Equal(t, "amount", rowErr.Column)
+
+	require.Error(t, cw.WriteRow(int64(99)))
+
+	require.NoError(t, cw.WriteRow(int64(3), pgxdecimal.Decimal(decimal128.MustParse("-0.000001")), ""))
+	require.Equal(t, int64(3), cw.Rows())
+	require.NoError(t, cw.Close())
+	require.Error(t, cw.WriteRow(int64(4), nil, nil))
+
+	return buf.Bytes()
+}
+
+func TestCopyWriter(t *testing.T) {
+	columns := copyWriterTestColumns(t)
+	data := writeCopyTestFile(t, columns)
+
+	require.True(t, bytes.HasPrefix(data, []byte("PGCOPY\n\377\r\n\000\000\000\000\000\000\000\000\000")))
+	require.True(t, bytes.HasSuffix(data, []byte{0xff, 0xff}))
+
+	dec := pgxdecimal.NewCopyDecoder(bytes.NewReader(data), newCopyTestMap(), columns, pgxdecimal.CopyOptions{Format: pgxdecimal.CopyBinary})
+	rows := scanCopyRows(t, dec)
+	require.Len(t, rows, 3)
+	require.Equal(t, "12.50", pgxdecimal.NumericOut(rows[0].amount.Decimal))
+	require.Equal(t, "a\tb\nc", *rows[0].note)
+	require.False(t, rows[1].amount.Valid)
+	require.Nil(t, rows[1].note)
+
+	// -0.000001 is rounded to the scale of numeric(10,2).
+	require.True(t, rows[2].amount.Valid)
+	require.True(t, rows[2].amount.Decimal.IsZero())
+
+	dec = pgxdecimal.NewCopyDecoder(bytes.NewReader(data), newCopyTestMap(), columns, pgxdecimal.CopyOptions{Format: pgxdecimal.CopyBinary})
+	require.True(t, dec.Next())
+	require.Equal(t, pgxdecimal.AppendNumericSend(nil, decimal128.MustParse("12.50")), dec.RawValues()[1])
+}
+
+func TestCopyWriterRoundsToScale(t *testing.T) {
+	columns := copyWriterTestColumns(t)
+
+	for _, tt := range []struct{ value, expected string }{
+		{"12.345", "12.35"},
+		{"-12.345", "-12.35"},
+		{"12.344", "12.34"},
+		{"12.5", "12.5"},
+		{"99999999.995", ""},
+	} {
+		var buf bytes.Buffer
+		cw := pgxdecimal.NewCopyWriter(&buf, nil, columns)
+		err := cw.WriteRow(int64(1), decimal128.MustParse(tt.value), nil)
+		if tt.expected == "" {
+			require.Error(t, err, tt.value)
+			continue
+		}
+		require.NoError(t, err, tt.value)
+		require.NoError(t, cw.Close())
+
+		dec := pgxdecimal.NewCopyDecoder(bytes.NewReader(buf.Bytes()), newCopyTestMap(), columns, pgxdecimal.CopyOptions{Format: pgxdecimal.CopyBinary})
+		require.True(t, dec.Next())
+		require.Equal(t, pgxdecimal.AppendNumericSend(nil, decimal128.MustParse(tt.expected)), dec.RawValues()[1], tt.value)
+	}
+}
+
+type failingWriter struct{}
+
+func (failingWriter) Write([]byte) (int, error) {
+	return 0, errors.New("disk full")
+}
+
+func TestCopyWriterError(t *testing.T) {
+	cw := pgxdecimal.NewCopyWriter(failingWriter{}, nil, copyWriterTestColumns(t))
+	require.NoError(t, cw.WriteRow(int64(1), decimal128.FromInt64(1), "x"))
+	require.EqualError(t, cw.Close(), "disk full")
+	require.EqualError(t, cw.WriteRow(int64(1), decimal128.FromInt64(1), "x"), "disk full")
+}
+
+func TestCopyWriterMatchServer(t *testing.T) {
+	defaultConnTestRunner.RunTest(context.Background(), t, func(ctx context.Context, t testing.TB, conn *pgx.Conn) {
+		_, err := conn.Exec(ctx, "create temporary table copy_file_decimals (id int8, amount numeric(10,2), note text)")
+		require.NoError(t, err)
+
+		data := writeCopyTestFile(t, copyWriterTestColumns(t))
+		tag, err := conn.PgConn().CopyFrom(ctx, bytes.NewReader(data), "copy copy_file_decimals from stdin (format binary)")
+		require.NoError(t, err)
+		require.Equal(t, int64(3), tag.RowsAffected())
+
+		rows, err := conn.Query(ctx, "select coalesce(amount::text, 'NULL') from copy_file_decimals order by id")
+		require.NoError(t, err)
+		amounts, err := pgx.CollectRows(rows, pgx.RowTo[string])
+		require.NoError(t, err)
+		require.Equal(t, []string{"12.50", "NULL", "0.00"}, amounts)
+	})
+}
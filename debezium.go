@@ -0,0 +1,237 @@
+package decimal
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ingothierack/decimal128"
+)
+
+var (
+	errDebeziumEmpty    = errors.New("empty Debezium decimal value")
+	errDebeziumSpecial  = errors.New("Debezium precise decimals cannot represent NaN or infinity")
+	errDebeziumScale    = errors.New("value has more digits after the decimal point than the Debezium schema scale")
+	errDebeziumSyntax   = errors.New("invalid Debezium string decimal")
+	errDebeziumNilValue = errors.New("Debezium VariableScaleDecimal has no value")
+)
+
+// AppendDebeziumPrecise appends the Debezium precise encoding of d to buf:
+// the unscaled value d * 10^scale as the big-endian two's complement bytes of
+// Kafka Connect's Decimal logical type, whose schema parameter is scale. The
+// JSON converter writes these bytes as base64. Trailing zeros beyond scale
+// are dropped, and errDebeziumScale is returned if d has other digits there.
+func AppendDebeziumPrecise(buf []byte, d decimal128.Decimal, scale int) ([]byte, error) {
+	if d.IsNaN() || d.IsInf(0) {
+		return nil, errDebeziumSpecial
+	}
+
+	v := newNumericVar(d)
+	coef := v.coef
+	switch {
+	case v.scale > scale:
+		q, r := new(big.Int).QuoRem(coef, pow10(v.scale-scale), new(big.Int))
+		if r.Sign() != 0 {
+			return nil, errDebeziumScale
+		}
+		coef = q
+	case v.scale < scale:
+		coef = new(big.Int).Mul(coef, pow10(scale-v.scale))
+	}
+
+	return appendTwosComplement(buf, coef), nil
+}
+
+// DecodeDebeziumPrecise decodes the Debezium precise encoding b of a value
+// whose schema scale is scale. Trailing zeros are dropped from values with
+// more than 34 digits, and values that still do not fit fail with
+// ErrNumericOverflow rather than being rounded.
+func DecodeDebeziumPrecise(b []byte, scale int) (decimal128.Decimal, error) {
+	if len(b) == 0 {
+		return decimal128.Decimal{}, errDebeziumEmpty
+	}
+
+	return unscaledDecimal(twosComplementInt(b), scale)
+}
+
+// DebeziumVariableScaleDecimal is Debezium's VariableScaleDecimal struct,
+// used in precise mode for numeric columns without a declared scale. It
+// marshals to and from JSON as the JSON converter writes it, with Value in
+// base64.
+type DebeziumVariableScaleDecimal struct {
+	Scale int32  `json:"scale"`
+	Value []byte `json:"value"`
+}
+
+// NewDebeziumVariableScaleDecimal returns the VariableScaleDecimal of d, with
+// the scale d was stored with.
+func NewDebeziumVariableScaleDecimal(d decimal128.Decimal) (DebeziumVariableScaleDecimal, error) {
+	if d.IsNaN() || d.IsInf(0) {
+		return DebeziumVariableScaleDecimal{}, errDebeziumSpecial
+	}
+
+	v := newNumericVar(d)
+	return DebeziumVariableScaleDecimal{Scale: int32(v.scale), Value: appendTwosComplement(nil, v.coef)}, nil
+}
+
+// Decimal returns the value of v, as DecodeDebeziumPrecise does.
+func (v DebeziumVariableScaleDecimal) Decimal() (decimal128.Decimal, error) {
+	return DecodeDebeziumPrecise(v.Value, int(v.Scale))
+}
+
+// DebeziumString returns d as Debezium's string mode writes numeric values:
+// plain notation keeping the scale, and NaN, Infinity or -Infinity.
+func DebeziumString(d decimal128.Decimal) string {
+	return NumericOut(d)
+}
+
+// DecodeDebeziumString decodes a value written in Debezium's string mode.
+// Values that do not fit fail as in DecodeDebeziumPrecise.
+func DecodeDebeziumString(s string) (decimal128.Decimal, error) {
+	switch {
+	case strings.EqualFold(s, "NaN"):
+		return decimal128.NaN(), nil
+	case strings.EqualFold(s, "Infinity"):
+		return decimal128.Inf(1), nil
+	case strings.EqualFold(s, "-Infinity"):
+		return decimal128.Inf(-1), nil
+	}
+
+	v, ok := parseNumericText(s)
+	if !ok {
+		return decimal128.Decimal{}, errDebeziumSyntax
+	}
+
+	return v.decimal()
+}
+
+// DebeziumPrecise returns the Debezium precise encoding of d at scale, or nil
+// if d is NULL.
+func (d NullDecimal) DebeziumPrecise(scale int) ([]byte, error) {
+	if !d.Valid {
+		return nil, nil
+	}
+
+	return AppendDebeziumPrecise(nil, d.Decimal, scale)
+}
+
+// ScanDebeziumPrecise sets d from the Debezium precise encoding b of a value
+// whose schema scale is scale. nil sets d to NULL.
+func (d *NullDecimal) ScanDebeziumPrecise(b []byte, scale int) error {
+	if b == nil {
+		*d = NullDecimal{}
+		return nil
+	}
+
+	dd, err := DecodeDebeziumPrecise(b, scale)
+	if err != nil {
+		return err
+	}
+
+	*d = NullDecimal{Decimal: dd, Valid: true}
+	return nil
+}
+
+// DebeziumVariableScale returns the VariableScaleDecimal of d, or nil if d is
+// NULL.
+func (d NullDecimal) DebeziumVariableScale() (*DebeziumVariableScaleDecimal, error) {
+	if !d.Valid {
+		return nil, nil
+	}
+
+	v, err := NewDebeziumVariableScaleDecimal(d.Decimal)
+	if err != nil {
+		return nil, err
+	}
+
+	return &v, nil
+}
+
+// ScanDebeziumVariableScale sets d from a VariableScaleDecimal. nil sets d to
+// NULL.
+func (d *NullDecimal) ScanDebeziumVariableScale(v *DebeziumVariableScaleDecimal) error {
+	if v == nil {
+		*d = NullDecimal{}
+		return nil
+	}
+	if v.Value == nil {
+		return errDebeziumNilValue
+	}
+
+	dd, err := v.Decimal()
+	if err != nil {
+		return err
+	}
+
+	*d = NullDecimal{Decimal: dd, Valid: true}
+	return nil
+}
+
+// DebeziumString returns d as Debezium's string mode writes it, or nil if d
+// is NULL.
+func (d NullDecimal) DebeziumString() *string {
+	if !d.Valid {
+		return nil
+	}
+
+	s := DebeziumString(d.Decimal)
+	return &s
+}
+
+// ScanDebeziumString sets d from a value written in Debezium's string mode.
+// nil sets d to NULL. NaN and infinity are rejected as by ScanNumeric.
+func (d *NullDecimal) ScanDebeziumString(s *string) error {
+	if s == nil {
+		*d = NullDecimal{}
+		return nil
+	}
+
+	dd, err := DecodeDebeziumString(*s)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case dd.IsNaN():
+		return errScanNaN
+	case dd.IsInf(0):
+		return fmt.Errorf(ErrScanInf, *s)
+	}
+
+	*d = NullDecimal{Decimal: dd, Valid: true}
+	return nil
+}
+
+// appendTwosComplement appends x to buf in the shortest big-endian two's
+// complement form, as Java's BigInteger.toByteArray returns it.
+func appendTwosComplement(buf []byte, x *big.Int) []byte {
+	if x.Sign() >= 0 {
+		b := x.Bytes()
+		if len(b) == 0 || b[0]&0x80 != 0 {
+			buf = append(buf, 0)
+		}
+		return append(buf, b...)
+	}
+
+	// -x - 1 has the bits of x inverted.
+	inv := new(big.Int).Not(x)
+	n := inv.BitLen()/8 + 1
+	b := inv.FillBytes(make([]byte, n))
+	for i := range b {
+		b[i] = ^b[i]
+	}
+
+	return append(buf, b...)
+}
+
+// twosComplementInt returns the integer with big-endian two's complement
+// bytes b.
+func twosComplementInt(b []byte) *big.Int {
+	x := new(big.Int).SetBytes(b)
+	if b[0]&0x80 != 0 {
+		x.Sub(x, new(big.Int).Lsh(big.NewInt(1), uint(len(b))*8))
+	}
+
+	return x
+}
@@ -0,0 +1,210 @@
+package decimal_test
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/ingothierack/decimal128"
+	pgxdecimal "github.com/ingothierack/pgx-woodsbury-decimal128"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDebeziumPrecise(t *testing.T) {
+	tests := []struct {
+		value string
+		scale int
+		b64   string
+	}{
+		{"12.50", 2, "BOI="},
+		{"12.5", 2, "BOI="},
+		{"12.5000", 2, "BOI="},
+		{"0", 2, "AA=="},
+		{"-0.01", 2, "/w=="},
+		{"1.28", 2, "AIA="},
+		{"-1.28", 2, "gA=="},
+		{"-1.29", 2, "/38="},
+		{"1000", -3, "AQ=="},
+	}
+
+	for _, tt := range tests {
+		b, err := pgxdecimal.AppendDebeziumPrecise(nil, decimal128.MustParse(tt.value), tt.scale)
+		require.NoError(t, err, tt.value)
+		require.Equal(t, tt.b64, base64.StdEncoding.EncodeToString(b), tt.value)
+
+		d, err := pgxdecimal.DecodeDebeziumPrecise(b, tt.scale)
+		require.NoError(t, err, tt.value)
+		require.Zero(t, d.Cmp(decimal128.MustParse(tt.value)), tt.value)
+	}
+
+	d, err := pgxdecimal.DecodeDebeziumPrecise([]byte{0x04, 0xe2}, 2)
+	require.NoError(t, err)
+	require.Equal(t, "12.50", pgxdecimal.NumericOut(d))
+
+	_, err = pgxdecimal.AppendDebeziumPrecise(nil, decimal128.MustParse("12.505"), 2)
+	require.Error(t, err)
+	_, err = pgxdecimal.AppendDebeziumPrecise(nil, decimal128.NaN(), 2)
+	require.Error(t, err)
+	_, err = pgxdecimal.DecodeDebeziumPrecise([]byte{}, 2)
+	require.Error(t, err)
+}
+
+func TestDebeziumPreciseOverflow(t *testing.T) {
+	digits35, _ := new(big.Int).SetString(strings.Repeat("9", 35), 10)
+	_, err := pgxdecimal.DecodeDebeziumPrecise(append([]byte{0}, digits35.Bytes()...), 2)
+	require.EqualError(t, err, pgxdecimal.ErrNumericOverflow)
+
+	trailingZeros, _ := new(big.Int).SetString(strings.Repeat("9", 34)+"00", 10)
+	d, err := pgxdecimal.DecodeDebeziumPrecise(append([]byte{0}, trailingZeros.Bytes()...), 2)
+	require.NoError(t, err)
+	require.Equal(t, strings.Repeat("9", 34), pgxdecimal.NumericOut(d))
+
+	_, err = pgxdecimal.DecodeDebeziumPrecise([]byte{1}, 1<<20)
+	require.EqualError(t, err, pgxdecimal.ErrNumericOverflow)
+}
+
+func TestDebeziumOverflowMatchesScan(t *testing.T) {
+	m := newCopyTestMap()
+
+	for _, s := range []string{
+		"12345678901234567890123456789012345",
+		"1234567890123456789012345678901234567890",
+		"12345678901234567890123456789012340",
+		"-0.00000000000000000000000000000000000001",
+	} {
+		var scanned pgxdecimal.NullDecimal
+		scanErr := m.Scan(pgtype.NumericOID, pgtype.TextFormatCode, []byte(s), &scanned)
+
+		var binScanned pgxdecimal.NullDecimal
+		binErr := m.Scan(pgtype.NumericOID, pgtype.BinaryFormatCode, numericSendBytes(t, m, s), &binScanned)
+		require.Equal(t, scanErr, binErr, s)
+
+		str, strErr := pgxdecimal.DecodeDebeziumString(s)
+		require.Equal(t, scanErr, strErr, s)
+
+		coef, _ := new(big.Int).SetString(strings.Replace(s, ".", "", 1), 10)
+		scale := 0
+		if _, frac, ok := strings.Cut(s, "."); ok {
+			scale = len(frac)
+		}
+		precise, preciseErr := pgxdecimal.DecodeDebeziumPrecise(twosComplementBytes(coef), scale)
+		require.Equal(t, scanErr, preciseErr, s)
+
+		if scanErr == nil {
+			require.Equal(t, scanned.Decimal, binScanned.Decimal, s)
+			require.Equal(t, scanned.Decimal, str, s)
+			require.Equal(t, scanned.Decimal, precise, s)
+		} else {
+			require.EqualError(t, scanErr, pgxdecimal.ErrNumericOverflow, s)
+		}
+	}
+}
+
+// twosComplementBytes returns x as big-endian two's complement bytes with a
+// leading sign byte.
+func twosComplementBytes(x *big.Int) []byte {
+	n := x.BitLen()/8 + 1
+	if x.Sign() < 0 {
+		x = new(big.Int).Add(x, new(big.Int).Lsh(big.NewInt(1), uint(n)*8))
+	}
+	return x.FillBytes(make([]byte, n))
+}
+
+func numericSendBytes(t testing.TB, m *pgtype.Map, s string) []byte {
+	var n pgtype.Numeric
+	require.NoError(t, n.Scan(s))
+	buf, err := m.Encode(pgtype.NumericOID, pgtype.BinaryFormatCode, n, nil)
+	require.NoError(t, err)
+	return buf
+}
+
+func TestDebeziumVariableScaleDecimal(t *testing.T) {
+	v, err := pgxdecimal.NewDebeziumVariableScaleDecimal(decimal128.MustParse("-12.50"))
+	require.NoError(t, err)
+
+	data, err := json.Marshal(v)
+	require.NoError(t, err)
+	require.JSONEq(t, `{"scale":2,"value":"+x4="}`, string(data))
+
+	var nd pgxdecimal.NullDecimal
+	var decoded *pgxdecimal.DebeziumVariableScaleDecimal
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	require.NoError(t, nd.ScanDebeziumVariableScale(decoded))
+	require.True(t, nd.Valid)
+	require.Equal(t, "-12.50", pgxdecimal.NumericOut(nd.Decimal))
+
+	decoded = nil
+	require.NoError(t, json.Unmarshal([]byte("null"), &decoded))
+	require.NoError(t, nd.ScanDebeziumVariableScale(decoded))
+	require.False(t, nd.Valid)
+
+	out, err := nd.DebeziumVariableScale()
+	require.NoError(t, err)
+	require.Nil(t, out)
+
+	require.Error(t, nd.ScanDebeziumVariableScale(&pgxdecimal.DebeziumVariableScaleDecimal{Scale: 2}))
+}
+
+func TestDebeziumString(t *testing.T) {
+	for _, s := range []string{"12.50", "-0.000001", "0", "NaN", "Infinity", "-Infinity"} {
+		d, err := pgxdecimal.DecodeDebeziumString(s)
+		require.NoError(t, err, s)
+		require.Equal(t, s, pgxdecimal.DebeziumString(d), s)
+	}
+
+	_, err := pgxdecimal.DecodeDebeziumString("1e5")
+	require.Error(t, err)
+	_, err = pgxdecimal.DecodeDebeziumString(strings.Repeat("9", 35))
+	require.EqualError(t, err, pgxdecimal.ErrNumericOverflow)
+
+	var nd pgxdecimal.NullDecimal
+	s := "100.00"
+	require.NoError(t, nd.ScanDebeziumString(&s))
+	require.Equal(t, "100.00", *nd.DebeziumString())
+
+	s = "NaN"
+	require.Error(t, nd.ScanDebeziumString(&s))
+	s = "-Infinity"
+	require.Error(t, nd.ScanDebeziumString(&s))
+
+	require.NoError(t, nd.ScanDebeziumString(nil))
+	require.False(t, nd.Valid)
+	require.Nil(t, nd.DebeziumString())
+}
+
+func TestNullDecimalDebeziumPrecise(t *testing.T) {
+	nd := pgxdecimal.NullDecimal{Decimal: decimal128.MustParse("7.5"), Valid: true}
+	b, err := nd.DebeziumPrecise(3)
+	require.NoError(t, err)
+	require.Equal(t, []byte{0x1d, 0x4c}, b)
+
+	var out pgxdecimal.NullDecimal
+	require.NoError(t, out.ScanDebeziumPrecise(b, 3))
+	require.Equal(t, "7.500", pgxdecimal.NumericOut(out.Decimal))
+
+	b, err = pgxdecimal.NullDecimal{}.DebeziumPrecise(3)
+	require.NoError(t, err)
+	require.Nil(t, b)
+	require.NoError(t, out.ScanDebeziumPrecise(nil, 3))
+	require.False(t, out.Valid)
+}
+
+func TestDebeziumMatchServer(t *testing.T) {
+	defaultConnTestRunner.RunTest(context.Background(), t, func(ctx context.Context, t testing.TB, conn *pgx.Conn) {
+		b, err := base64.StdEncoding.DecodeString("+x4=")
+		require.NoError(t, err)
+
+		var nd pgxdecimal.NullDecimal
+		require.NoError(t, nd.ScanDebeziumPrecise(b, 2))
+
+		var text string
+		err = conn.QueryRow(ctx, "select $1::numeric(10,2)::text", nd).Scan(&text)
+		require.NoError(t, err)
+		require.Equal(t, "-12.50", text)
+	})
+}
@@ -1,6 +1,7 @@
 package decimal
 
 import (
+	"database/sql"
 	"fmt"
 	"math"
 	"math/big"
@@ -44,11 +45,12 @@ func (d *Decimal) ScanNumeric(v pgtype.Numeric) error {
 		return fmt.Errorf(ErrScanInf, v.InfinityModifier)
 	}
 
-	if v.Int.BitLen() > 128 {
-		panic("bitlen outside of range")
+	dd, err := composeDecimal(v)
+	if err != nil {
+		return err
 	}
 
-	*d = Decimal(composeDecimal(v))
+	*d = Decimal(dd)
 
 	return nil
 }
@@ -87,18 +89,12 @@ func (d *Decimal) ScanFloat64(v pgtype.Float8) error {
 		return errScanNull
 	}
 
-	floatVal := v.Float64
-
-	if math.IsNaN(floatVal) {
-		return errScanNaN
-	}
-
-	if math.IsInf(floatVal, 0) {
-		return fmt.Errorf(ErrScanInf, floatVal)
+	dd, err := parseFloat64(v.Float64)
+	if err != nil {
+		return err
 	}
 
-	s := strconv.FormatFloat(floatVal, 'f', -1, 64)
-	*d = Decimal(decimal128.MustParse(s))
+	*d = Decimal(dd)
 	return nil
 }
 
@@ -151,7 +147,12 @@ func (d *NullDecimal) ScanNumeric(v pgtype.Numeric) error {
 		return fmt.Errorf(ErrScanInf, v.InfinityModifier)
 	}
 
-	*d = NullDecimal{Decimal: composeDecimal(v), Valid: true}
+	dd, err := composeDecimal(v)
+	if err != nil {
+		return err
+	}
+
+	*d = NullDecimal{Decimal: dd, Valid: true}
 	return nil
 }
 
@@ -183,16 +184,12 @@ func (d *NullDecimal) ScanFloat64(v pgtype.Float8) error {
 		return nil
 	}
 
-	if math.IsNaN(v.Float64) {
-		return errScanNaN
-	}
-
-	if math.IsInf(v.Float64, 0) {
-		return fmt.Errorf(ErrScanInf, v.Float64)
+	dd, err := parseFloat64(v.Float64)
+	if err != nil {
+		return err
 	}
 
-	s := strconv.FormatFloat(v.Float64, 'f', -1, 64)
-	*d = NullDecimal{Decimal: decimal128.MustParse(s), Valid: true}
+	*d = NullDecimal{Decimal: dd, Valid: true}
 	return nil
 
 }
@@ -240,6 +237,10 @@ func TryWrapNumericEncodePlan(value any) (plan pgtype.WrappedEncodePlanNextSette
 		return &wrapDecimalEncodePlan{}, Decimal(value), true
 	case NullDecimal:
 		return &wrapNullDecimalEncodePlan{}, NullDecimal(value), true
+	case sql.Null[decimal128.Decimal]:
+		return &wrapSQLNullEncodePlan{}, FromSQLNull(value), true
+	case sql.Null[Decimal]:
+		return &wrapSQLNullDecimalEncodePlan{}, FromSQLNullDecimal(value), true
 	}
 
 	return nil, nil, false
@@ -362,14 +363,50 @@ func Register(m *pgtype.Map) {
 	registerDefaultPgTypeVariants("numeric", "_numeric", decimal128.Decimal{})
 	registerDefaultPgTypeVariants("numeric", "_numeric", Decimal{})
 	registerDefaultPgTypeVariants("numeric", "_numeric", NullDecimal{})
+	registerDefaultPgTypeVariants("numeric", "_numeric", sql.Null[decimal128.Decimal]{})
+	registerDefaultPgTypeVariants("numeric", "_numeric", sql.Null[Decimal]{})
 }
 
-func composeDecimal(v pgtype.Numeric) decimal128.Decimal {
-	dec := decimal128.Decimal{}
-	if v.Int.Sign() < 0 {
-		dec.Compose(0, true, v.Int.Bytes(), v.Exp)
-	} else {
-		dec.Compose(0, false, v.Int.Bytes(), v.Exp)
+// composeDecimal converts the finite v into a decimal128.Decimal. Trailing
+// zeros are dropped if v has more than 34 digits, and errNumericOverflow is
+// returned if that is not enough to make it fit, as by numericVar.decimal.
+func composeDecimal(v pgtype.Numeric) (decimal128.Decimal, error) {
+	if v.Int == nil {
+		return decimal128.Decimal{}, nil
 	}
-	return dec
+
+	return unscaledDecimal(v.Int, -int(v.Exp))
+}
+
+// parseFloat64 converts f to a decimal128.Decimal via its shortest decimal
+// representation, rejecting NaN and infinite values.
+func parseFloat64(f float64) (decimal128.Decimal, error) {
+	if math.IsNaN(f) {
+		return decimal128.Decimal{}, errScanNaN
+	}
+
+	if math.IsInf(f, 0) {
+		return decimal128.Decimal{}, fmt.Errorf(ErrScanInf, f)
+	}
+
+	s := strconv.FormatFloat(f, 'f', -1, 64)
+	return decimal128.MustParse(s), nil
+}
+
+// appendText appends d to buf in plain notation, keeping the number of digits
+// after the decimal point. d must be finite.
+func appendText(buf []byte, d decimal128.Decimal) []byte {
+	if d.IsZero() {
+		d = decimal128.Abs(d)
+	}
+
+	var sig [16]byte
+	_, _, _, exp := d.Decompose(sig[:0])
+
+	prec := 0
+	if exp < 0 {
+		prec = int(-exp)
+	}
+
+	return decimal128.Append(buf, d, 'f', prec)
 }
@@ -100,6 +100,16 @@ func BenchmarkDecimalNumericValue(b *testing.B) {
 	}
 }
 
+// Benchmark the binary numeric encoding used by CopyFrom
+func BenchmarkAppendNumericSend(b *testing.B) {
+	buf := make([]byte, 0, 64)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		buf = pgxdecimal.AppendNumericSend(buf[:0], benchmarkDecimal128Values[i%len(benchmarkDecimal128Values)])
+	}
+}
+
 // Benchmark Decimal.ScanFloat64
 func BenchmarkDecimalScanFloat64(b *testing.B) {
 	var d pgxdecimal.Decimal
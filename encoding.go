@@ -0,0 +1,189 @@
+package decimal
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/ingothierack/decimal128"
+)
+
+const nullText = "NULL"
+
+var errInvalidNullDecimalBinary = errors.New("invalid NullDecimal binary encoding")
+
+// MarshalText implements the encoding TextMarshaler interface. Finite values
+// are written in plain notation with their scale preserved.
+func (d Decimal) MarshalText() ([]byte, error) {
+	return appendDecimalText(nil, decimal128.Decimal(d)), nil
+}
+
+// UnmarshalText implements the encoding TextUnmarshaler interface.
+func (d *Decimal) UnmarshalText(text []byte) error {
+	dd, err := decimal128.Parse(string(text))
+	if err != nil {
+		return err
+	}
+
+	*d = Decimal(dd)
+	return nil
+}
+
+// MarshalBinary implements the encoding BinaryMarshaler interface using the
+// 16 byte decimal128 encoding.
+func (d Decimal) MarshalBinary() ([]byte, error) {
+	return decimal128.Decimal(d).MarshalBinary()
+}
+
+// UnmarshalBinary implements the encoding BinaryUnmarshaler interface.
+func (d *Decimal) UnmarshalBinary(data []byte) error {
+	return (*decimal128.Decimal)(d).UnmarshalBinary(data)
+}
+
+// GobEncode implements the encoding/gob GobEncoder interface.
+func (d Decimal) GobEncode() ([]byte, error) {
+	return d.MarshalBinary()
+}
+
+// GobDecode implements the encoding/gob GobDecoder interface.
+func (d *Decimal) GobDecode(data []byte) error {
+	return d.UnmarshalBinary(data)
+}
+
+// Format implements the fmt Formatter interface. %v prints the String form,
+// padded to the requested width, and %s and %q print it as a string. The
+// other verbs of decimal128.Decimal (%e, %E, %f, %F, %g and %G) are formatted
+// by decimal128 with width, precision and flags.
+func (d Decimal) Format(f fmt.State, verb rune) {
+	switch verb {
+	case 'v':
+		formatPadded(f, appendDecimalText(nil, decimal128.Decimal(d)))
+	case 's', 'q':
+		fmt.Fprintf(f, fmt.FormatString(f, verb), appendDecimalText(nil, decimal128.Decimal(d)))
+	default:
+		decimal128.Decimal(d).Format(f, verb)
+	}
+}
+
+// formatPadded writes text to f, padded to the width of f.
+func formatPadded(f fmt.State, text []byte) {
+	width, _ := f.Width()
+	if f.Flag('-') {
+		fmt.Fprintf(f, "%-*s", width, text)
+	} else {
+		fmt.Fprintf(f, "%*s", width, text)
+	}
+}
+
+// LogValue implements the log/slog LogValuer interface.
+func (d Decimal) LogValue() slog.Value {
+	return slog.StringValue(string(appendDecimalText(nil, decimal128.Decimal(d))))
+}
+
+// MarshalText implements the encoding TextMarshaler interface. An invalid
+// NullDecimal is written as NULL.
+func (d NullDecimal) MarshalText() ([]byte, error) {
+	if !d.Valid {
+		return []byte(nullText), nil
+	}
+
+	return Decimal(d.Decimal).MarshalText()
+}
+
+// UnmarshalText implements the encoding TextUnmarshaler interface. NULL sets
+// d to an invalid NullDecimal.
+func (d *NullDecimal) UnmarshalText(text []byte) error {
+	if string(text) == nullText {
+		*d = NullDecimal{}
+		return nil
+	}
+
+	dd, err := decimal128.Parse(string(text))
+	if err != nil {
+		return err
+	}
+
+	*d = NullDecimal{Decimal: dd, Valid: true}
+	return nil
+}
+
+// MarshalBinary implements the encoding BinaryMarshaler interface. The
+// encoding is a validity byte followed by the 16 byte decimal128 encoding for
+// valid values.
+func (d NullDecimal) MarshalBinary() ([]byte, error) {
+	if !d.Valid {
+		return []byte{0}, nil
+	}
+
+	return d.Decimal.AppendBinary([]byte{1})
+}
+
+// UnmarshalBinary implements the encoding BinaryUnmarshaler interface.
+func (d *NullDecimal) UnmarshalBinary(data []byte) error {
+	if len(data) == 0 {
+		return errInvalidNullDecimalBinary
+	}
+
+	switch data[0] {
+	case 0:
+		if len(data) != 1 {
+			return errInvalidNullDecimalBinary
+		}
+		*d = NullDecimal{}
+		return nil
+	case 1:
+		var dd decimal128.Decimal
+		if err := dd.UnmarshalBinary(data[1:]); err != nil {
+			return err
+		}
+		*d = NullDecimal{Decimal: dd, Valid: true}
+		return nil
+	}
+
+	return errInvalidNullDecimalBinary
+}
+
+// GobEncode implements the encoding/gob GobEncoder interface.
+func (d NullDecimal) GobEncode() ([]byte, error) {
+	return d.MarshalBinary()
+}
+
+// GobDecode implements the encoding/gob GobDecoder interface.
+func (d *NullDecimal) GobDecode(data []byte) error {
+	return d.UnmarshalBinary(data)
+}
+
+// Format implements the fmt Formatter interface. An invalid NullDecimal is
+// printed as NULL, padded to the requested width.
+func (d NullDecimal) Format(f fmt.State, verb rune) {
+	if !d.Valid {
+		formatPadded(f, []byte(nullText))
+		return
+	}
+
+	Decimal(d.Decimal).Format(f, verb)
+}
+
+// LogValue implements the log/slog LogValuer interface.
+func (d NullDecimal) LogValue() slog.Value {
+	if !d.Valid {
+		return slog.StringValue(nullText)
+	}
+
+	return Decimal(d.Decimal).LogValue()
+}
+
+// appendDecimalText appends d in plain notation, spelling NaN and infinite
+// values the way PostgreSQL does.
+func appendDecimalText(buf []byte, d decimal128.Decimal) []byte {
+	switch {
+	case d.IsNaN():
+		return append(buf, "NaN"...)
+	case d.IsInf(1):
+		return append(buf, "Infinity"...)
+	case d.IsInf(-1):
+		return append(buf, "-Infinity"...)
+	}
+
+	return appendText(buf, d)
+}
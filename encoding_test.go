@@ -0,0 +1,147 @@
+package decimal_test
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/gob"
+	"fmt"
+	"log/slog"
+	"testing"
+
+	"github.com/ingothierack/decimal128"
+	pgxdecimal "github.com/ingothierack/pgx-woodsbury-decimal128"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	_ encoding.TextMarshaler     = pgxdecimal.Decimal{}
+	_ encoding.TextUnmarshaler   = (*pgxdecimal.Decimal)(nil)
+	_ encoding.BinaryMarshaler   = pgxdecimal.NullDecimal{}
+	_ encoding.BinaryUnmarshaler = (*pgxdecimal.NullDecimal)(nil)
+	_ fmt.Formatter              = pgxdecimal.NullDecimal{}
+	_ slog.LogValuer             = pgxdecimal.Decimal{}
+)
+
+func TestDecimalText(t *testing.T) {
+	for _, tt := range []struct {
+		value    decimal128.Decimal
+		expected string
+	}{
+		{value: decimal128.MustParse("1.00"), expected: "1.00"},
+		{value: decimal128.MustParse("-9345678901234567890.123456789012345"), expected: "-9345678901234567890.123456789012345"},
+		{value: decimal128.NaN(), expected: "NaN"},
+		{value: decimal128.Inf(1), expected: "Infinity"},
+	} {
+		text, err := pgxdecimal.Decimal(tt.value).MarshalText()
+		require.NoError(t, err)
+		require.Equal(t, tt.expected, string(text))
+
+		var d pgxdecimal.Decimal
+		require.NoError(t, d.UnmarshalText(text))
+		require.Equal(t, tt.value.IsNaN(), decimal128.Decimal(d).IsNaN())
+		if !tt.value.IsNaN() {
+			require.True(t, decimal128.Decimal(d).Equal(tt.value))
+		}
+	}
+
+	text, err := pgxdecimal.NullDecimal{}.MarshalText()
+	require.NoError(t, err)
+	require.Equal(t, "NULL", string(text))
+
+	nd := pgxdecimal.NullDecimal{Decimal: decimal128.FromInt64(1), Valid: true}
+	require.NoError(t, nd.UnmarshalText([]byte("NULL")))
+	require.False(t, nd.Valid)
+	require.NoError(t, nd.UnmarshalText([]byte("-2.5")))
+	require.True(t, nd.Valid)
+	require.True(t, nd.Decimal.Equal(decimal128.MustParse("-2.5")))
+}
+
+func TestDecimalBinary(t *testing.T) {
+	d := pgxdecimal.Decimal(decimal128.MustParse("-123456.123456"))
+	buf, err := d.MarshalBinary()
+	require.NoError(t, err)
+	require.Len(t, buf, 16)
+
+	var out pgxdecimal.Decimal
+	require.NoError(t, out.UnmarshalBinary(buf))
+	require.Equal(t, d, out)
+
+	for _, nd := range []pgxdecimal.NullDecimal{
+		{Decimal: decimal128.MustParse("0.000012345"), Valid: true},
+		{},
+	} {
+		buf, err := nd.MarshalBinary()
+		require.NoError(t, err)
+
+		var out pgxdecimal.NullDecimal
+		require.NoError(t, out.UnmarshalBinary(buf))
+		require.Equal(t, nd, out)
+	}
+
+	var nd pgxdecimal.NullDecimal
+	require.Error(t, nd.UnmarshalBinary(nil))
+	require.Error(t, nd.UnmarshalBinary([]byte{0, 1}))
+	require.Error(t, nd.UnmarshalBinary([]byte{2}))
+}
+
+func TestDecimalGob(t *testing.T) {
+	type record struct {
+		Amount pgxdecimal.Decimal
+		Fee    pgxdecimal.NullDecimal
+		Tax    pgxdecimal.NullDecimal
+	}
+
+	in := record{
+		Amount: pgxdecimal.Decimal(decimal128.MustParse("12345678901234567.89")),
+		Fee:    pgxdecimal.NullDecimal{Decimal: decimal128.MustParse("0.25"), Valid: true},
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, gob.NewEncoder(&buf).Encode(in))
+
+	var out record
+	require.NoError(t, gob.NewDecoder(&buf).Decode(&out))
+	require.Equal(t, in, out)
+}
+
+func TestDecimalFormat(t *testing.T) {
+	d := pgxdecimal.Decimal(decimal128.MustParse("1234.5678"))
+	require.Equal(t, "1234.57", fmt.Sprintf("%.2f", d))
+	require.Equal(t, "   1234.57", fmt.Sprintf("%10.2f", d))
+	require.Equal(t, "1.234568e+03", fmt.Sprintf("%e", d))
+	require.Equal(t, "1234.5678", fmt.Sprintf("%v", d))
+	require.Equal(t, "1234.5678 ", fmt.Sprintf("%-10s", d))
+	require.Equal(t, `"1234.5678"`, fmt.Sprintf("%q", d))
+
+	large := pgxdecimal.Decimal(decimal128.MustParse("1e21"))
+	require.Equal(t, large.String(), fmt.Sprint(large))
+	require.Equal(t, "1000000000000000000000", fmt.Sprintf("%v", large))
+	require.Equal(t, "1000000000000000000000", fmt.Sprintf("%s", large))
+	require.Equal(t, "1e+21", fmt.Sprintf("%g", large))
+	require.Equal(t, "[NaN -Infinity]", fmt.Sprint([]pgxdecimal.Decimal{pgxdecimal.Decimal(decimal128.NaN()), pgxdecimal.Decimal(decimal128.Inf(-1))}))
+	require.Equal(t, "1000000000000000000000", fmt.Sprint(pgxdecimal.NullDecimal{Decimal: decimal128.MustParse("1e21"), Valid: true}))
+
+	nd := pgxdecimal.NullDecimal{Decimal: decimal128.MustParse("1234.5678"), Valid: true}
+	require.Equal(t, "1234.6", fmt.Sprintf("%.1f", nd))
+	require.Equal(t, "NULL", fmt.Sprintf("%.2f", pgxdecimal.NullDecimal{}))
+	require.Equal(t, "  NULL", fmt.Sprintf("%6v", pgxdecimal.NullDecimal{}))
+	require.Equal(t, "NULL  |", fmt.Sprintf("%-6v|", pgxdecimal.NullDecimal{}))
+}
+
+func TestDecimalLogValue(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			if a.Key == slog.TimeKey {
+				return slog.Attr{}
+			}
+			return a
+		},
+	}))
+
+	logger.Info("values",
+		"amount", pgxdecimal.Decimal(decimal128.MustParse("10.50")),
+		"fee", pgxdecimal.NullDecimal{},
+	)
+	require.Equal(t, "level=INFO msg=values amount=10.50 fee=NULL\n", buf.String())
+}
@@ -0,0 +1,115 @@
+package decimal
+
+import (
+	"encoding/json"
+
+	"github.com/ingothierack/decimal128"
+)
+
+// MarshalJSON implements the encoding/json Marshaler interface. Finite values
+// are written as exact JSON numbers in plain notation. NaN and infinite values
+// have no JSON number form and are always quoted.
+func (d Decimal) MarshalJSON() ([]byte, error) {
+	dd := decimal128.Decimal(d)
+	if !dd.IsNaN() && !dd.IsInf(0) {
+		return appendText(nil, dd), nil
+	}
+
+	return appendJSONString(dd), nil
+}
+
+// UnmarshalJSON implements the encoding/json Unmarshaler interface. It accepts
+// JSON numbers and strings and parses them without going through float64. A
+// JSON null leaves d unchanged.
+func (d *Decimal) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		return nil
+	}
+
+	if len(data) > 0 && data[0] == '"' {
+		var s string
+		if err := json.Unmarshal(data, &s); err != nil {
+			return err
+		}
+
+		dd, err := decimal128.Parse(s)
+		if err != nil {
+			return err
+		}
+
+		*d = Decimal(dd)
+		return nil
+	}
+
+	return (*decimal128.Decimal)(d).UnmarshalJSON(data)
+}
+
+// MarshalJSON implements the encoding/json Marshaler interface. An invalid
+// NullDecimal is written as null.
+func (d NullDecimal) MarshalJSON() ([]byte, error) {
+	if !d.Valid {
+		return []byte("null"), nil
+	}
+
+	return Decimal(d.Decimal).MarshalJSON()
+}
+
+// UnmarshalJSON implements the encoding/json Unmarshaler interface. A JSON
+// null sets d to an invalid NullDecimal.
+func (d *NullDecimal) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		*d = NullDecimal{}
+		return nil
+	}
+
+	var dd Decimal
+	if err := dd.UnmarshalJSON(data); err != nil {
+		return err
+	}
+
+	*d = NullDecimal{Decimal: decimal128.Decimal(dd), Valid: true}
+	return nil
+}
+
+// DecimalString is a Decimal that is written as a JSON string instead of a
+// JSON number, for consumers that parse JSON numbers as float64. Use it for
+// the fields that need it, e.g. pgxdecimal.DecimalString(d).
+type DecimalString Decimal
+
+// MarshalJSON implements the encoding/json Marshaler interface.
+func (d DecimalString) MarshalJSON() ([]byte, error) {
+	return appendJSONString(decimal128.Decimal(d)), nil
+}
+
+// UnmarshalJSON implements the encoding/json Unmarshaler interface as
+// Decimal.UnmarshalJSON does.
+func (d *DecimalString) UnmarshalJSON(data []byte) error {
+	return (*Decimal)(d).UnmarshalJSON(data)
+}
+
+// NullDecimalString is a NullDecimal that is written as a JSON string
+// instead of a JSON number. An invalid NullDecimalString is written as null.
+type NullDecimalString NullDecimal
+
+// MarshalJSON implements the encoding/json Marshaler interface.
+func (d NullDecimalString) MarshalJSON() ([]byte, error) {
+	if !d.Valid {
+		return []byte("null"), nil
+	}
+
+	return appendJSONString(d.Decimal), nil
+}
+
+// UnmarshalJSON implements the encoding/json Unmarshaler interface as
+// NullDecimal.UnmarshalJSON does.
+func (d *NullDecimalString) UnmarshalJSON(data []byte) error {
+	return (*NullDecimal)(d).UnmarshalJSON(data)
+}
+
+// appendJSONString returns d as a quoted JSON string, in plain notation for
+// finite values.
+func appendJSONString(d decimal128.Decimal) []byte {
+	buf := append(make([]byte, 0, 40), '"')
+	buf = appendDecimalText(buf, d)
+	return append(buf, '"')
+}
@@ -0,0 +1,99 @@
+package decimal_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/ingothierack/decimal128"
+	pgxdecimal "github.com/ingothierack/pgx-woodsbury-decimal128"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecimalMarshalJSON(t *testing.T) {
+	for _, tt := range []struct {
+		value    pgxdecimal.Decimal
+		expected string
+	}{
+		{value: pgxdecimal.Decimal(decimal128.MustParse("1.00")), expected: `1.00`},
+		{value: pgxdecimal.Decimal(decimal128.MustParse("-0.000012345")), expected: `-0.000012345`},
+		{value: pgxdecimal.Decimal(decimal128.MustParse("12345678901234567.89")), expected: `12345678901234567.89`},
+		{value: pgxdecimal.Decimal(decimal128.MustParse("1e21")), expected: `1000000000000000000000`},
+		{value: pgxdecimal.Decimal(decimal128.NaN()), expected: `"NaN"`},
+		{value: pgxdecimal.Decimal(decimal128.Inf(1)), expected: `"Infinity"`},
+	} {
+		buf, err := json.Marshal(tt.value)
+		require.NoError(t, err)
+		require.Equal(t, tt.expected, string(buf))
+	}
+
+	buf, err := json.Marshal(struct {
+		A pgxdecimal.NullDecimal
+		B pgxdecimal.NullDecimal
+	}{
+		A: pgxdecimal.NullDecimal{Decimal: decimal128.MustParse("2.50"), Valid: true},
+	})
+	require.NoError(t, err)
+	require.Equal(t, `{"A":2.50,"B":null}`, string(buf))
+}
+
+func TestDecimalStringMarshalJSON(t *testing.T) {
+	buf, err := json.Marshal(pgxdecimal.DecimalString(decimal128.MustParse("-123.4500")))
+	require.NoError(t, err)
+	require.Equal(t, `"-123.4500"`, string(buf))
+
+	buf, err = json.Marshal(struct {
+		A pgxdecimal.NullDecimalString
+		B pgxdecimal.NullDecimalString
+		C pgxdecimal.Decimal
+	}{
+		A: pgxdecimal.NullDecimalString{Decimal: decimal128.MustParse("1e21"), Valid: true},
+		C: pgxdecimal.Decimal(decimal128.MustParse("2.50")),
+	})
+	require.NoError(t, err)
+	require.Equal(t, `{"A":"1000000000000000000000","B":null,"C":2.50}`, string(buf))
+
+	var v struct {
+		A pgxdecimal.DecimalString
+		B pgxdecimal.NullDecimalString
+	}
+	require.NoError(t, json.Unmarshal([]byte(`{"A":"-123.4500","B":null}`), &v))
+	require.True(t, decimal128.Decimal(v.A).Equal(decimal128.MustParse("-123.45")))
+	require.False(t, v.B.Valid)
+}
+
+func TestDecimalUnmarshalJSON(t *testing.T) {
+	for _, data := range []string{
+		`12345678901234567.89`,
+		`"12345678901234567.89"`,
+		`1.234567890123456789e16`,
+	} {
+		var d pgxdecimal.Decimal
+		require.NoError(t, json.Unmarshal([]byte(data), &d), data)
+		require.True(t, decimal128.Decimal(d).Equal(decimal128.MustParse("12345678901234567.89")), data)
+	}
+
+	buf, err := json.Marshal(json.Number("0.1000000000000000055511151231257827"))
+	require.NoError(t, err)
+	var d pgxdecimal.Decimal
+	require.NoError(t, json.Unmarshal(buf, &d))
+	require.True(t, decimal128.Decimal(d).Equal(decimal128.MustParse("0.1000000000000000055511151231257827")))
+
+	require.Error(t, json.Unmarshal([]byte(`"abc"`), &d))
+	require.Error(t, json.Unmarshal([]byte(`[1]`), &d))
+}
+
+func TestNullDecimalUnmarshalJSON(t *testing.T) {
+	var v struct {
+		A pgxdecimal.NullDecimal
+		B pgxdecimal.NullDecimal
+		C pgxdecimal.NullDecimal
+	}
+	v.B = pgxdecimal.NullDecimal{Decimal: decimal128.FromInt64(1), Valid: true}
+
+	require.NoError(t, json.Unmarshal([]byte(`{"A":"-7.25","B":null,"C":3}`), &v))
+	require.True(t, v.A.Valid)
+	require.True(t, v.A.Decimal.Equal(decimal128.MustParse("-7.25")))
+	require.False(t, v.B.Valid)
+	require.True(t, v.C.Valid)
+	require.True(t, v.C.Decimal.Equal(decimal128.FromInt64(3)))
+}
@@ -0,0 +1,140 @@
+package decimal
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"reflect"
+
+	"github.com/ingothierack/decimal128"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+var errJSONTrailingData = errors.New("invalid character after top-level JSON value")
+
+var jsonNumberType = reflect.TypeFor[json.Number]()
+
+// UnmarshalJSONDecimal decodes data into v like json.Unmarshal, but never
+// routes JSON numbers through float64. Numbers decoded into interface values,
+// such as the values of a map[string]any, become decimal128.Decimal.
+// decimal128.Decimal, Decimal and NullDecimal fields are decoded exactly by
+// their own UnmarshalJSON methods.
+func UnmarshalJSONDecimal(data []byte, v any) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+
+	if err := dec.Decode(v); err != nil {
+		return err
+	}
+
+	if _, err := dec.Token(); err != io.EOF {
+		return errJSONTrailingData
+	}
+
+	return replaceJSONNumbers(reflect.ValueOf(v))
+}
+
+// RegisterJSON replaces the json and jsonb codecs of m with codecs that decode
+// with UnmarshalJSONDecimal. Values are still encoded with json.Marshal, which
+// writes decimal128.Decimal, Decimal and NullDecimal values as exact numbers.
+func RegisterJSON(m *pgtype.Map) {
+	jsonType := &pgtype.Type{
+		Name:  "json",
+		OID:   pgtype.JSONOID,
+		Codec: &pgtype.JSONCodec{Marshal: json.Marshal, Unmarshal: UnmarshalJSONDecimal},
+	}
+	jsonbType := &pgtype.Type{
+		Name:  "jsonb",
+		OID:   pgtype.JSONBOID,
+		Codec: &pgtype.JSONBCodec{Marshal: json.Marshal, Unmarshal: UnmarshalJSONDecimal},
+	}
+
+	m.RegisterType(jsonType)
+	m.RegisterType(jsonbType)
+	m.RegisterType(&pgtype.Type{Name: "_json", OID: pgtype.JSONArrayOID, Codec: &pgtype.ArrayCodec{ElementType: jsonType}})
+	m.RegisterType(&pgtype.Type{Name: "_jsonb", OID: pgtype.JSONBArrayOID, Codec: &pgtype.ArrayCodec{ElementType: jsonbType}})
+}
+
+// replaceJSONNumbers walks v and replaces every json.Number held in an
+// interface value with the equivalent decimal128.Decimal. Fields explicitly
+// typed as json.Number are left alone.
+func replaceJSONNumbers(v reflect.Value) error {
+	switch v.Kind() {
+	case reflect.Pointer:
+		if v.IsNil() {
+			return nil
+		}
+		return replaceJSONNumbers(v.Elem())
+
+	case reflect.Interface:
+		if v.IsNil() || !v.CanSet() {
+			return nil
+		}
+
+		elem := v.Elem()
+		if elem.Type() == jsonNumberType {
+			dd, err := decimal128.Parse(elem.String())
+			if err != nil {
+				return err
+			}
+			v.Set(reflect.ValueOf(dd))
+			return nil
+		}
+
+		c := reflect.New(elem.Type()).Elem()
+		c.Set(elem)
+		if err := replaceJSONNumbers(c); err != nil {
+			return err
+		}
+		v.Set(c)
+
+	case reflect.Struct:
+		t := v.Type()
+		for i := range v.NumField() {
+			if !t.Field(i).IsExported() {
+				continue
+			}
+			if err := replaceJSONNumbers(v.Field(i)); err != nil {
+				return err
+			}
+		}
+
+	case reflect.Slice, reflect.Array:
+		if !mayHoldJSONNumber(v.Type().Elem()) {
+			return nil
+		}
+
+		for i := range v.Len() {
+			if err := replaceJSONNumbers(v.Index(i)); err != nil {
+				return err
+			}
+		}
+
+	case reflect.Map:
+		if v.IsNil() || !mayHoldJSONNumber(v.Type().Elem()) {
+			return nil
+		}
+
+		iter := v.MapRange()
+		for iter.Next() {
+			c := reflect.New(v.Type().Elem()).Elem()
+			c.Set(iter.Value())
+			if err := replaceJSONNumbers(c); err != nil {
+				return err
+			}
+			v.SetMapIndex(iter.Key(), c)
+		}
+	}
+
+	return nil
+}
+
+func mayHoldJSONNumber(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Interface, reflect.Pointer, reflect.Struct, reflect.Slice, reflect.Array, reflect.Map:
+		return true
+	}
+
+	return false
+}
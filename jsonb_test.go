@@ -0,0 +1,68 @@
+package decimal_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/ingothierack/decimal128"
+	pgxdecimal "github.com/ingothierack/pgx-woodsbury-decimal128"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnmarshalJSONDecimal(t *testing.T) {
+	data := []byte(`{"amount": 12345678901234567.89, "items": [1, 0.1], "count": "3", "exact": 2.5}`)
+
+	var m map[string]any
+	require.NoError(t, pgxdecimal.UnmarshalJSONDecimal(data, &m))
+	require.Equal(t, decimal128.MustParse("12345678901234567.89"), m["amount"])
+	require.Equal(t, []any{decimal128.MustParse("1"), decimal128.MustParse("0.1")}, m["items"])
+	require.Equal(t, "3", m["count"])
+
+	var s struct {
+		Amount decimal128.Decimal
+		Items  []any
+		Count  pgxdecimal.NullDecimal
+		Exact  json.Number
+	}
+	require.NoError(t, pgxdecimal.UnmarshalJSONDecimal(data, &s))
+	require.True(t, s.Amount.Equal(decimal128.MustParse("12345678901234567.89")))
+	require.Equal(t, []any{decimal128.MustParse("1"), decimal128.MustParse("0.1")}, s.Items)
+	require.True(t, s.Count.Valid)
+	require.True(t, s.Count.Decimal.Equal(decimal128.FromInt64(3)))
+	require.Equal(t, json.Number("2.5"), s.Exact)
+
+	var v any
+	require.NoError(t, pgxdecimal.UnmarshalJSONDecimal([]byte(`-0.000012345`), &v))
+	require.Equal(t, decimal128.MustParse("-0.000012345"), v)
+
+	require.Error(t, pgxdecimal.UnmarshalJSONDecimal([]byte(`1 2`), &v))
+	require.Error(t, pgxdecimal.UnmarshalJSONDecimal([]byte(`{"a":1e99999}`), &m))
+}
+
+func TestRegisterJSON(t *testing.T) {
+	m := pgtype.NewMap()
+	pgxdecimal.Register(m)
+	pgxdecimal.RegisterJSON(m)
+
+	type payload struct {
+		Amount pgxdecimal.Decimal `json:"amount"`
+	}
+
+	for _, oid := range []uint32{pgtype.JSONOID, pgtype.JSONBOID} {
+		buf, err := m.Encode(oid, pgtype.TextFormatCode, payload{Amount: pgxdecimal.Decimal(decimal128.MustParse("12345678901234567.89"))}, nil)
+		require.NoError(t, err)
+		require.Equal(t, `{"amount":12345678901234567.89}`, string(buf))
+
+		var result map[string]any
+		require.NoError(t, m.Scan(oid, pgtype.TextFormatCode, buf, &result))
+		require.Equal(t, decimal128.MustParse("12345678901234567.89"), result["amount"])
+	}
+
+	buf, err := m.Encode(pgtype.JSONBArrayOID, pgtype.TextFormatCode, []map[string]any{{"a": 1}}, nil)
+	require.NoError(t, err)
+
+	var result []map[string]any
+	require.NoError(t, m.Scan(pgtype.JSONBArrayOID, pgtype.TextFormatCode, buf, &result))
+	require.Equal(t, []map[string]any{{"a": decimal128.MustParse("1")}}, result)
+}
@@ -0,0 +1,59 @@
+package decimal
+
+import (
+	"github.com/ingothierack/decimal128"
+)
+
+// Key is a comparable form of a numeric value for use as a Go map key or in
+// sets. Values that are equal in PostgreSQL have equal keys, even if their
+// decimal128 representations differ: 1, 1.0 and 1.00 share a key, as do 0
+// and -0. NaN values share a key of their own, distinct from every number,
+// and NULL has the zero Key, as in GROUP BY and DISTINCT.
+type Key struct {
+	d     decimal128.Decimal
+	valid bool
+}
+
+// NewKey returns the key of d.
+func NewKey(d decimal128.Decimal) Key {
+	// Canonical keeps the sign of zero.
+	if d.IsZero() {
+		return Key{d: decimal128.FromInt64(0), valid: true}
+	}
+
+	return Key{d: d.Canonical(), valid: true}
+}
+
+// Key returns the key of d.
+func (d Decimal) Key() Key {
+	return NewKey(decimal128.Decimal(d))
+}
+
+// Key returns the key of d, which is the zero Key if d is NULL.
+func (d NullDecimal) Key() Key {
+	if !d.Valid {
+		return Key{}
+	}
+
+	return NewKey(d.Decimal)
+}
+
+// IsNull reports whether k is the key of NULL.
+func (k Key) IsNull() bool {
+	return !k.valid
+}
+
+// Decimal returns the value of k in canonical form, with trailing zeros
+// removed, or NULL.
+func (k Key) Decimal() NullDecimal {
+	return NullDecimal{Decimal: k.d, Valid: k.valid}
+}
+
+// String returns the value of k as NumericOut formats it, or "NULL".
+func (k Key) String() string {
+	if !k.valid {
+		return "NULL"
+	}
+
+	return NumericOut(k.d)
+}
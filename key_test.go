@@ -0,0 +1,56 @@
+package decimal_test
+
+import (
+	"testing"
+
+	"github.com/ingothierack/decimal128"
+	pgxdecimal "github.com/ingothierack/pgx-woodsbury-decimal128"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKey(t *testing.T) {
+	for _, group := range [][]string{
+		{"1", "1.0", "1.00", "0.1e1", "100e-2"},
+		{"0", "-0", "0.000", "0e10", "-0e-20"},
+		{"-12.5", "-12.50", "-1250e-2"},
+		{"1e30", "1000000000000000000000000000000", "1.0e30"},
+		{"NaN", "-NaN"},
+		{"Infinity", "+Infinity"},
+		{"-Infinity"},
+	} {
+		key := pgxdecimal.NewKey(decimal128.MustParse(group[0]))
+		for _, s := range group[1:] {
+			require.Equal(t, key, pgxdecimal.NewKey(decimal128.MustParse(s)), "%s and %s", group[0], s)
+		}
+	}
+
+	// NaN payloads are ignored.
+	nan := decimal128.FromInt64(0).Quo(decimal128.FromInt64(0))
+	require.True(t, nan.IsNaN())
+	require.Equal(t, pgxdecimal.NewKey(decimal128.NaN()), pgxdecimal.NewKey(nan))
+
+	keys := map[pgxdecimal.Key]int{}
+	for _, s := range []string{"1", "1.0", "-1", "0", "-0", "NaN", "Infinity", "-Infinity", "1e-30"} {
+		keys[pgxdecimal.Decimal(decimal128.MustParse(s)).Key()]++
+	}
+	keys[pgxdecimal.NullDecimal{}.Key()]++
+	keys[pgxdecimal.NullDecimal{}.Key()]++
+	require.Len(t, keys, 8)
+	require.Equal(t, 2, keys[pgxdecimal.NewKey(decimal128.FromInt64(1))])
+	require.Equal(t, 2, keys[pgxdecimal.NewKey(decimal128.FromInt64(0))])
+	require.Equal(t, 2, keys[pgxdecimal.Key{}])
+}
+
+func TestKeyValue(t *testing.T) {
+	key := pgxdecimal.NullDecimal{Decimal: decimal128.MustParse("-8.4100"), Valid: true}.Key()
+	require.False(t, key.IsNull())
+	require.Equal(t, "-8.41", key.String())
+	require.Equal(t, "-8.41", pgxdecimal.Decimal(key.Decimal().Decimal).String())
+
+	require.Equal(t, "0", pgxdecimal.NewKey(decimal128.MustParse("-0.00")).String())
+	require.Equal(t, "NaN", pgxdecimal.NewKey(decimal128.NaN()).String())
+
+	require.True(t, pgxdecimal.Key{}.IsNull())
+	require.Equal(t, "NULL", pgxdecimal.Key{}.String())
+	require.False(t, pgxdecimal.Key{}.Decimal().Valid)
+}
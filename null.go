@@ -0,0 +1,113 @@
+package decimal
+
+import (
+	"database/sql"
+
+	"github.com/ingothierack/decimal128"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// FromSQLNull converts a sql.Null[decimal128.Decimal] into a NullDecimal.
+func FromSQLNull(n sql.Null[decimal128.Decimal]) NullDecimal {
+	return NullDecimal{Decimal: n.V, Valid: n.Valid}
+}
+
+// FromSQLNullDecimal converts a sql.Null[Decimal] into a NullDecimal.
+func FromSQLNullDecimal(n sql.Null[Decimal]) NullDecimal {
+	return NullDecimal{Decimal: decimal128.Decimal(n.V), Valid: n.Valid}
+}
+
+// SQLNull converts d into a sql.Null[decimal128.Decimal].
+func (d NullDecimal) SQLNull() sql.Null[decimal128.Decimal] {
+	return sql.Null[decimal128.Decimal]{V: d.Decimal, Valid: d.Valid}
+}
+
+// SQLNullDecimal converts d into a sql.Null[Decimal].
+func (d NullDecimal) SQLNullDecimal() sql.Null[Decimal] {
+	return sql.Null[Decimal]{V: Decimal(d.Decimal), Valid: d.Valid}
+}
+
+// PlanScan plans sql.Null targets through NullDecimal. This has to happen in
+// the codec because pgx prefers the sql.Scanner implementation of sql.Null
+// over TryWrapScanPlanFuncs.
+func (c NumericCodec) PlanScan(m *pgtype.Map, oid uint32, format int16, target any) pgtype.ScanPlan {
+	if plan, nextDst, ok := tryWrapSQLNullScanPlan(target); ok {
+		if next := c.NumericCodec.PlanScan(m, oid, format, nextDst); next != nil {
+			plan.SetNext(next)
+			return plan
+		}
+	}
+
+	return c.NumericCodec.PlanScan(m, oid, format, target)
+}
+
+func tryWrapSQLNullScanPlan(target any) (plan pgtype.WrappedScanPlanNextSetter, nextDst any, ok bool) {
+	switch target.(type) {
+	case *sql.Null[decimal128.Decimal]:
+		return &wrapSQLNullScanPlan{}, &NullDecimal{}, true
+	case *sql.Null[Decimal]:
+		return &wrapSQLNullDecimalScanPlan{}, &NullDecimal{}, true
+	}
+
+	return nil, nil, false
+}
+
+type wrapSQLNullScanPlan struct {
+	next pgtype.ScanPlan
+}
+
+func (plan *wrapSQLNullScanPlan) SetNext(next pgtype.ScanPlan) {
+	plan.next = next
+}
+
+func (plan *wrapSQLNullScanPlan) Scan(src []byte, dst any) error {
+	var nd NullDecimal
+	if err := plan.next.Scan(src, &nd); err != nil {
+		return err
+	}
+
+	*dst.(*sql.Null[decimal128.Decimal]) = nd.SQLNull()
+	return nil
+}
+
+type wrapSQLNullDecimalScanPlan struct {
+	next pgtype.ScanPlan
+}
+
+func (plan *wrapSQLNullDecimalScanPlan) SetNext(next pgtype.ScanPlan) {
+	plan.next = next
+}
+
+func (plan *wrapSQLNullDecimalScanPlan) Scan(src []byte, dst any) error {
+	var nd NullDecimal
+	if err := plan.next.Scan(src, &nd); err != nil {
+		return err
+	}
+
+	*dst.(*sql.Null[Decimal]) = nd.SQLNullDecimal()
+	return nil
+}
+
+type wrapSQLNullEncodePlan struct {
+	next pgtype.EncodePlan
+}
+
+func (plan *wrapSQLNullEncodePlan) SetNext(next pgtype.EncodePlan) {
+	plan.next = next
+}
+
+func (plan *wrapSQLNullEncodePlan) Encode(value any, buf []byte) (newBuf []byte, err error) {
+	return plan.next.Encode(FromSQLNull(value.(sql.Null[decimal128.Decimal])), buf)
+}
+
+type wrapSQLNullDecimalEncodePlan struct {
+	next pgtype.EncodePlan
+}
+
+func (plan *wrapSQLNullDecimalEncodePlan) SetNext(next pgtype.EncodePlan) {
+	plan.next = next
+}
+
+func (plan *wrapSQLNullDecimalEncodePlan) Encode(value any, buf []byte) (newBuf []byte, err error) {
+	return plan.next.Encode(FromSQLNullDecimal(value.(sql.Null[Decimal])), buf)
+}
@@ -0,0 +1,75 @@
+package decimal_test
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/ingothierack/decimal128"
+	pgxdecimal "github.com/ingothierack/pgx-woodsbury-decimal128"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSQLNullConversion(t *testing.T) {
+	nd := pgxdecimal.NullDecimal{Decimal: decimal128.MustParse("1.25"), Valid: true}
+
+	require.Equal(t, sql.Null[decimal128.Decimal]{V: nd.Decimal, Valid: true}, nd.SQLNull())
+	require.Equal(t, sql.Null[pgxdecimal.Decimal]{V: pgxdecimal.Decimal(nd.Decimal), Valid: true}, nd.SQLNullDecimal())
+	require.Equal(t, nd, pgxdecimal.FromSQLNull(nd.SQLNull()))
+	require.Equal(t, nd, pgxdecimal.FromSQLNullDecimal(nd.SQLNullDecimal()))
+
+	require.False(t, pgxdecimal.NullDecimal{}.SQLNull().Valid)
+	require.False(t, pgxdecimal.FromSQLNullDecimal(sql.Null[pgxdecimal.Decimal]{}).Valid)
+}
+
+func TestSQLNullEncodeScan(t *testing.T) {
+	m := pgtype.NewMap()
+	pgxdecimal.Register(m)
+
+	for _, format := range []int16{pgtype.TextFormatCode, pgtype.BinaryFormatCode} {
+		value := sql.Null[decimal128.Decimal]{V: decimal128.MustParse("-123456.123456"), Valid: true}
+		buf, err := m.Encode(pgtype.NumericOID, format, value, nil)
+		require.NoError(t, err)
+
+		var result sql.Null[decimal128.Decimal]
+		require.NoError(t, m.Scan(pgtype.NumericOID, format, buf, &result))
+		require.True(t, result.Valid)
+		require.True(t, result.V.Equal(value.V))
+
+		var resultDecimal sql.Null[pgxdecimal.Decimal]
+		require.NoError(t, m.Scan(pgtype.NumericOID, format, buf, &resultDecimal))
+		require.True(t, resultDecimal.Valid)
+		require.True(t, decimal128.Decimal(resultDecimal.V).Equal(value.V))
+
+		buf, err = m.Encode(pgtype.NumericOID, format, sql.Null[pgxdecimal.Decimal]{}, nil)
+		require.NoError(t, err)
+		require.Nil(t, buf)
+
+		require.NoError(t, m.Scan(pgtype.NumericOID, format, nil, &result))
+		require.False(t, result.Valid)
+	}
+}
+
+func TestSQLNullArray(t *testing.T) {
+	m := pgtype.NewMap()
+	pgxdecimal.Register(m)
+
+	input := []sql.Null[decimal128.Decimal]{
+		{V: decimal128.MustParse("1.5"), Valid: true},
+		{},
+		{V: decimal128.MustParse("-2"), Valid: true},
+	}
+
+	for _, format := range []int16{pgtype.TextFormatCode, pgtype.BinaryFormatCode} {
+		buf, err := m.Encode(pgtype.NumericArrayOID, format, input, nil)
+		require.NoError(t, err)
+
+		var output []sql.Null[decimal128.Decimal]
+		require.NoError(t, m.Scan(pgtype.NumericArrayOID, format, buf, &output))
+		require.Len(t, output, len(input))
+		for i := range input {
+			require.Equal(t, input[i].Valid, output[i].Valid)
+			require.True(t, input[i].V.Equal(output[i].V))
+		}
+	}
+}
@@ -0,0 +1,179 @@
+package decimal
+
+import (
+	"errors"
+
+	"github.com/ingothierack/decimal128"
+)
+
+const ErrDivisionByZero = "division by zero"
+
+var errDivisionByZero = errors.New(ErrDivisionByZero)
+
+// Add returns d + o, or NULL if either operand is NULL.
+func (d NullDecimal) Add(o NullDecimal) NullDecimal {
+	if !d.Valid || !o.Valid {
+		return NullDecimal{}
+	}
+
+	return NullDecimal{Decimal: d.Decimal.Add(o.Decimal), Valid: true}
+}
+
+// Sub returns d - o, or NULL if either operand is NULL.
+func (d NullDecimal) Sub(o NullDecimal) NullDecimal {
+	if !d.Valid || !o.Valid {
+		return NullDecimal{}
+	}
+
+	return NullDecimal{Decimal: d.Decimal.Sub(o.Decimal), Valid: true}
+}
+
+// Mul returns d * o, or NULL if either operand is NULL.
+func (d NullDecimal) Mul(o NullDecimal) NullDecimal {
+	if !d.Valid || !o.Valid {
+		return NullDecimal{}
+	}
+
+	return NullDecimal{Decimal: d.Decimal.Mul(o.Decimal), Valid: true}
+}
+
+// Div returns d / o as NumericDiv computes it, or NULL if either operand is
+// NULL. As in PostgreSQL, dividing a non-NaN value by zero fails.
+func (d NullDecimal) Div(o NullDecimal) (NullDecimal, error) {
+	if !d.Valid || !o.Valid {
+		return NullDecimal{}, nil
+	}
+
+	q, err := NumericDiv(d.Decimal, o.Decimal)
+	if err != nil {
+		return NullDecimal{}, err
+	}
+
+	return NullDecimal{Decimal: q, Valid: true}, nil
+}
+
+// Neg returns -d, or NULL if d is NULL.
+func (d NullDecimal) Neg() NullDecimal {
+	if !d.Valid {
+		return NullDecimal{}
+	}
+
+	return NullDecimal{Decimal: d.Decimal.Neg(), Valid: true}
+}
+
+// Abs returns the absolute value of d, or NULL if d is NULL.
+func (d NullDecimal) Abs() NullDecimal {
+	if !d.Valid {
+		return NullDecimal{}
+	}
+
+	return NullDecimal{Decimal: decimal128.Abs(d.Decimal), Valid: true}
+}
+
+// Cmp compares d and o using PostgreSQL numeric ordering, where NaN is equal
+// to itself and greater than any other value. The boolean result is false,
+// like a SQL comparison yielding NULL, if either operand is NULL.
+func (d NullDecimal) Cmp(o NullDecimal) (int, bool) {
+	if !d.Valid || !o.Valid {
+		return 0, false
+	}
+
+	return cmpNumeric(d.Decimal, o.Decimal), true
+}
+
+// CoalesceNull returns the first non-NULL value, or NULL if all values are
+// NULL.
+func CoalesceNull(values ...NullDecimal) NullDecimal {
+	for _, v := range values {
+		if v.Valid {
+			return v
+		}
+	}
+
+	return NullDecimal{}
+}
+
+// CountNull returns the number of non-NULL values, like count(x).
+func CountNull(values []NullDecimal) int64 {
+	var n int64
+	for _, v := range values {
+		if v.Valid {
+			n++
+		}
+	}
+
+	return n
+}
+
+// SumNull returns the sum of the non-NULL values, or NULL if there are none,
+// like sum(x).
+func SumNull(values []NullDecimal) NullDecimal {
+	var sum NullDecimal
+	for _, v := range values {
+		if !v.Valid {
+			continue
+		}
+
+		if !sum.Valid {
+			sum = v
+			continue
+		}
+
+		sum.Decimal = sum.Decimal.Add(v.Decimal)
+	}
+
+	return sum
+}
+
+// AvgNull returns the mean of the non-NULL values, or NULL if there are none,
+// like avg(x). It is computed by NumericAgg, with the result scale of
+// PostgreSQL.
+func AvgNull(values []NullDecimal) (NullDecimal, error) {
+	var agg NumericAgg
+	for _, v := range values {
+		agg.AddNullDecimal(v)
+	}
+
+	return agg.Avg()
+}
+
+// MinNull returns the smallest non-NULL value, or NULL if there are none, like
+// min(x).
+func MinNull(values []NullDecimal) NullDecimal {
+	var m NullDecimal
+	for _, v := range values {
+		if v.Valid && (!m.Valid || cmpNumeric(v.Decimal, m.Decimal) < 0) {
+			m = v
+		}
+	}
+
+	return m
+}
+
+// MaxNull returns the largest non-NULL value, or NULL if there are none, like
+// max(x).
+func MaxNull(values []NullDecimal) NullDecimal {
+	var m NullDecimal
+	for _, v := range values {
+		if v.Valid && (!m.Valid || cmpNumeric(v.Decimal, m.Decimal) > 0) {
+			m = v
+		}
+	}
+
+	return m
+}
+
+// cmpNumeric compares a and b the way PostgreSQL compares numeric values: NaN
+// is equal to NaN and greater than every other value.
+func cmpNumeric(a, b decimal128.Decimal) int {
+	switch {
+	case a.IsNaN() && b.IsNaN():
+		return 0
+	case a.IsNaN():
+		return 1
+	case b.IsNaN():
+		return -1
+	}
+
+	return int(a.Cmp(b))
+}
@@ -0,0 +1,105 @@
+package decimal_test
+
+import (
+	"testing"
+
+	"github.com/ingothierack/decimal128"
+	pgxdecimal "github.com/ingothierack/pgx-woodsbury-decimal128"
+	"github.com/stretchr/testify/require"
+)
+
+func nd(s string) pgxdecimal.NullDecimal {
+	return pgxdecimal.NullDecimal{Decimal: decimal128.MustParse(s), Valid: true}
+}
+
+func requireNullDecimal(t *testing.T, expected, actual pgxdecimal.NullDecimal) {
+	t.Helper()
+	require.Equal(t, expected.Valid, actual.Valid)
+	if expected.Valid {
+		require.True(t, expected.Decimal.Equal(actual.Decimal), "expected %v, got %v", expected, actual)
+	}
+}
+
+func TestNullDecimalArithmetic(t *testing.T) {
+	null := pgxdecimal.NullDecimal{}
+
+	requireNullDecimal(t, nd("3.5"), nd("1.25").Add(nd("2.25")))
+	requireNullDecimal(t, null, nd("1.25").Add(null))
+	requireNullDecimal(t, nd("-1"), nd("1.25").Sub(nd("2.25")))
+	requireNullDecimal(t, null, null.Sub(nd("1")))
+	requireNullDecimal(t, nd("2.8125"), nd("1.25").Mul(nd("2.25")))
+	requireNullDecimal(t, null, nd("1.25").Mul(null))
+	requireNullDecimal(t, nd("-1.25"), nd("1.25").Neg())
+	requireNullDecimal(t, null, null.Neg())
+	requireNullDecimal(t, nd("1.25"), nd("-1.25").Abs())
+	requireNullDecimal(t, null, null.Abs())
+
+	// The quotients have the result scale of PostgreSQL's numeric division.
+	for _, tt := range []struct{ a, b, expected string }{
+		{"10", "4", "2.5000000000000000"},
+		{"1.5", "3", "0.50000000000000000000"},
+		{"1", "3", "0.33333333333333333333"},
+		{"-2", "3", "-0.66666666666666666667"},
+	} {
+		q, err := nd(tt.a).Div(nd(tt.b))
+		require.NoError(t, err)
+		require.Equal(t, tt.expected, pgxdecimal.NumericOut(q.Decimal), "%s / %s", tt.a, tt.b)
+	}
+
+	q, err := nd("10").Div(nd("4"))
+	require.NoError(t, err)
+	requireNullDecimal(t, nd("2.5"), q)
+
+	_, err = nd("10").Div(nd("0"))
+	require.EqualError(t, err, pgxdecimal.ErrDivisionByZero)
+
+	q, err = null.Div(nd("0"))
+	require.NoError(t, err)
+	requireNullDecimal(t, null, q)
+
+	q, err = nd("NaN").Div(nd("0"))
+	require.NoError(t, err)
+	require.True(t, q.Decimal.IsNaN())
+}
+
+func TestNullDecimalCmpCoalesce(t *testing.T) {
+	c, ok := nd("1.0").Cmp(nd("1.00"))
+	require.True(t, ok)
+	require.Equal(t, 0, c)
+
+	c, ok = nd("NaN").Cmp(nd("1e100"))
+	require.True(t, ok)
+	require.Equal(t, 1, c)
+
+	_, ok = nd("1").Cmp(pgxdecimal.NullDecimal{})
+	require.False(t, ok)
+
+	requireNullDecimal(t, nd("2"), pgxdecimal.CoalesceNull(pgxdecimal.NullDecimal{}, nd("2"), nd("3")))
+	requireNullDecimal(t, pgxdecimal.NullDecimal{}, pgxdecimal.CoalesceNull(pgxdecimal.NullDecimal{}))
+	requireNullDecimal(t, pgxdecimal.NullDecimal{}, pgxdecimal.CoalesceNull())
+}
+
+func TestNullDecimalAggregates(t *testing.T) {
+	values := []pgxdecimal.NullDecimal{nd("1.5"), {}, nd("-2"), nd("4"), {}}
+
+	require.Equal(t, int64(3), pgxdecimal.CountNull(values))
+	requireNullDecimal(t, nd("3.5"), pgxdecimal.SumNull(values))
+	avg, err := pgxdecimal.AvgNull(values)
+	require.NoError(t, err)
+	require.Equal(t, "1.16666666666666666667", pgxdecimal.NumericOut(avg.Decimal))
+	requireNullDecimal(t, nd("-2"), pgxdecimal.MinNull(values))
+	requireNullDecimal(t, nd("4"), pgxdecimal.MaxNull(values))
+
+	nulls := []pgxdecimal.NullDecimal{{}, {}}
+	require.Equal(t, int64(0), pgxdecimal.CountNull(nulls))
+	requireNullDecimal(t, pgxdecimal.NullDecimal{}, pgxdecimal.SumNull(nulls))
+	avg, err = pgxdecimal.AvgNull(nulls)
+	require.NoError(t, err)
+	requireNullDecimal(t, pgxdecimal.NullDecimal{}, avg)
+	requireNullDecimal(t, pgxdecimal.NullDecimal{}, pgxdecimal.MinNull(nil))
+	requireNullDecimal(t, pgxdecimal.NullDecimal{}, pgxdecimal.MaxNull(nil))
+
+	withNaN := []pgxdecimal.NullDecimal{nd("1"), nd("NaN"), nd("Inf")}
+	require.True(t, pgxdecimal.MaxNull(withNaN).Decimal.IsNaN())
+	requireNullDecimal(t, nd("1"), pgxdecimal.MinNull(withNaN))
+}
@@ -0,0 +1,194 @@
+package decimal
+
+import (
+	"math/big"
+
+	"github.com/ingothierack/decimal128"
+)
+
+// NumericAgg accumulates values the way PostgreSQL's numeric aggregates do,
+// so that sum, avg, var_pop, var_samp, stddev_pop and stddev_samp computed in
+// Go match the server's output. Sums are kept exactly, and a result that does
+// not fit in decimal128 is reported with ErrNumericOverflow when it is read.
+//
+// The zero value is an empty accumulator ready to use. Partial accumulators can
+// be combined with Merge.
+type NumericAgg struct {
+	n         int64
+	nanCount  int64
+	pInfCount int64
+	nInfCount int64
+	sumX      numericVar
+	sumX2     numericVar
+}
+
+// Add adds x to the aggregate.
+func (a *NumericAgg) Add(x decimal128.Decimal) {
+	switch {
+	case x.IsNaN():
+		a.nanCount++
+		return
+	case x.IsInf(1):
+		a.pInfCount++
+		return
+	case x.IsInf(-1):
+		a.nInfCount++
+		return
+	}
+
+	a.init()
+
+	v := newNumericVar(x)
+	a.sumX = addVar(a.sumX, v)
+	a.sumX2 = addVar(a.sumX2, mulVar(v, v))
+	a.n++
+}
+
+// AddNullDecimal adds x to the aggregate unless it is NULL, which aggregates
+// ignore.
+func (a *NumericAgg) AddNullDecimal(x NullDecimal) {
+	if x.Valid {
+		a.Add(x.Decimal)
+	}
+}
+
+// Merge adds the values accumulated by o to a.
+func (a *NumericAgg) Merge(o *NumericAgg) {
+	a.nanCount += o.nanCount
+	a.pInfCount += o.pInfCount
+	a.nInfCount += o.nInfCount
+
+	if o.n > 0 {
+		a.init()
+		a.n += o.n
+		a.sumX = addVar(a.sumX, o.sumX)
+		a.sumX2 = addVar(a.sumX2, o.sumX2)
+	}
+}
+
+// Count returns the number of values added, like count(x).
+func (a *NumericAgg) Count() int64 {
+	return a.n + a.nanCount + a.pInfCount + a.nInfCount
+}
+
+// Sum returns the sum of the values, like sum(numeric). It is NULL if no values
+// were added.
+func (a *NumericAgg) Sum() (NullDecimal, error) {
+	if a.Count() == 0 {
+		return NullDecimal{}, nil
+	}
+
+	if special, ok := a.sumSpecial(); ok {
+		return NullDecimal{Decimal: special, Valid: true}, nil
+	}
+
+	return nullDecimalResult(a.sumX)
+}
+
+// Avg returns the mean of the values, like avg(numeric), with the result scale
+// of PostgreSQL's numeric division. It is NULL if no values were added.
+func (a *NumericAgg) Avg() (NullDecimal, error) {
+	if a.Count() == 0 {
+		return NullDecimal{}, nil
+	}
+
+	if special, ok := a.sumSpecial(); ok {
+		return NullDecimal{Decimal: special, Valid: true}, nil
+	}
+
+	n := numericVar{coef: big.NewInt(a.n)}
+	return nullDecimalResult(divVar(a.sumX, n, selectDivScale(a.sumX, n), true))
+}
+
+// VarPop returns the population variance of the values, like
+// var_pop(numeric). It is NULL if no values were added.
+func (a *NumericAgg) VarPop() (NullDecimal, error) {
+	return a.stddev(true, false)
+}
+
+// VarSamp returns the sample variance of the values, like var_samp(numeric)
+// and variance(numeric). It is NULL if fewer than two values were added.
+func (a *NumericAgg) VarSamp() (NullDecimal, error) {
+	return a.stddev(true, true)
+}
+
+// StddevPop returns the population standard deviation of the values, like
+// stddev_pop(numeric). It is NULL if no values were added.
+func (a *NumericAgg) StddevPop() (NullDecimal, error) {
+	return a.stddev(false, false)
+}
+
+// StddevSamp returns the sample standard deviation of the values, like
+// stddev_samp(numeric) and stddev(numeric). It is NULL if fewer than two
+// values were added.
+func (a *NumericAgg) StddevSamp() (NullDecimal, error) {
+	return a.stddev(false, true)
+}
+
+func (a *NumericAgg) init() {
+	if a.sumX.coef == nil {
+		a.sumX = numericVar{coef: new(big.Int)}
+		a.sumX2 = numericVar{coef: new(big.Int)}
+	}
+}
+
+// sumSpecial returns the result of sum and avg if a NaN or infinity was added.
+func (a *NumericAgg) sumSpecial() (decimal128.Decimal, bool) {
+	switch {
+	case a.nanCount > 0, a.pInfCount > 0 && a.nInfCount > 0:
+		return decimal128.NaN(), true
+	case a.pInfCount > 0:
+		return decimal128.Inf(1), true
+	case a.nInfCount > 0:
+		return decimal128.Inf(-1), true
+	}
+
+	return decimal128.Decimal{}, false
+}
+
+// stddev is numeric_stddev_internal from numeric.c.
+func (a *NumericAgg) stddev(variance, sample bool) (NullDecimal, error) {
+	count := a.Count()
+	if count == 0 || sample && count <= 1 {
+		return NullDecimal{}, nil
+	}
+
+	// As for float8, any infinite input produces NaN.
+	if a.nanCount > 0 || a.pInfCount > 0 || a.nInfCount > 0 {
+		return NullDecimal{Decimal: decimal128.NaN(), Valid: true}, nil
+	}
+
+	n := numericVar{coef: big.NewInt(a.n)}
+
+	// N * sumX2 - sumX * sumX
+	rscale := a.sumX.scale * 2
+	num := subVar(roundVar(mulVar(n, a.sumX2), rscale, true), roundVar(mulVar(a.sumX, a.sumX), rscale, true))
+
+	// Round-off cannot occur here, but a non-positive numerator yields zero
+	// as in PostgreSQL.
+	if num.coef.Sign() <= 0 {
+		return nullDecimalResult(numericVar{coef: new(big.Int)})
+	}
+
+	den := mulVar(n, n)
+	if sample {
+		den = mulVar(n, numericVar{coef: big.NewInt(a.n - 1)})
+	}
+
+	rscale = selectDivScale(num, den)
+	r := divVar(num, den, rscale, true)
+	if !variance {
+		r = sqrtVar(r, rscale)
+	}
+
+	return nullDecimalResult(r)
+}
+
+func nullDecimalResult(v numericVar) (NullDecimal, error) {
+	d, err := v.decimal()
+	if err != nil {
+		return NullDecimal{}, err
+	}
+
+	return NullDecimal{Decimal: d, Valid: true}, nil
+}
@@ -0,0 +1,149 @@
+package decimal_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ingothierack/decimal128"
+	pgxdecimal "github.com/ingothierack/pgx-woodsbury-decimal128"
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/require"
+)
+
+type numericAggTest struct {
+	values                                           []string
+	sum, avg, varPop, varSamp, stddevPop, stddevSamp string
+}
+
+// numericAggTests holds the text output of PostgreSQL for the numeric
+// aggregates over the values, with NULL results spelled "NULL".
+// TestNumericAggMatchServer checks them against a live server.
+var numericAggTests = []numericAggTest{
+	{
+		values: []string{"1", "2", "3", "4"},
+		sum:    "10", avg: "2.5000000000000000",
+		varPop: "1.2500000000000000", varSamp: "1.6666666666666667",
+		stddevPop: "1.1180339887498948", stddevSamp: "1.2909944487358056",
+	},
+	{
+		values: []string{"1.10", "2.205", "-0.5"},
+		sum:    "2.805", avg: "0.93500000000000000000",
+		varPop: "1.2331166666666667", varSamp: "1.8496750000000000",
+		stddevPop: "1.1104578635259722", stddevSamp: "1.3600275732498955",
+	},
+	{
+		values: []string{"5"},
+		sum:    "5", avg: "5.0000000000000000",
+		varPop: "0", varSamp: "NULL",
+		stddevPop: "0", stddevSamp: "NULL",
+	},
+	{
+		values: []string{},
+		sum:    "NULL", avg: "NULL",
+		varPop: "NULL", varSamp: "NULL",
+		stddevPop: "NULL", stddevSamp: "NULL",
+	},
+	{
+		values: []string{"1", "NaN"},
+		sum:    "NaN", avg: "NaN",
+		varPop: "NaN", varSamp: "NaN",
+		stddevPop: "NaN", stddevSamp: "NaN",
+	},
+	{
+		values: []string{"Infinity", "1"},
+		sum:    "Infinity", avg: "Infinity",
+		varPop: "NaN", varSamp: "NaN",
+		stddevPop: "NaN", stddevSamp: "NaN",
+	},
+	{
+		values: []string{"Infinity", "-Infinity"},
+		sum:    "NaN", avg: "NaN",
+		varPop: "NaN", varSamp: "NaN",
+		stddevPop: "NaN", stddevSamp: "NaN",
+	},
+}
+
+// nullDecimalText returns a function converting a NullDecimal result into
+// text, with NULL spelled "NULL".
+func nullDecimalText(t testing.TB) func(pgxdecimal.NullDecimal, error) string {
+	return func(nd pgxdecimal.NullDecimal, err error) string {
+		require.NoError(t, err)
+		if !nd.Valid {
+			return "NULL"
+		}
+		return pgxdecimal.Decimal(nd.Decimal).String()
+	}
+}
+
+func TestNumericAgg(t *testing.T) {
+	text := nullDecimalText(t)
+	for _, tt := range numericAggTests {
+		var agg pgxdecimal.NumericAgg
+		for _, v := range tt.values {
+			agg.Add(decimal128.MustParse(v))
+		}
+
+		require.Equal(t, int64(len(tt.values)), agg.Count())
+		require.Equal(t, tt.sum, text(agg.Sum()), "sum%v", tt.values)
+		require.Equal(t, tt.avg, text(agg.Avg()), "avg%v", tt.values)
+		require.Equal(t, tt.varPop, text(agg.VarPop()), "var_pop%v", tt.values)
+		require.Equal(t, tt.varSamp, text(agg.VarSamp()), "var_samp%v", tt.values)
+		require.Equal(t, tt.stddevPop, text(agg.StddevPop()), "stddev_pop%v", tt.values)
+		require.Equal(t, tt.stddevSamp, text(agg.StddevSamp()), "stddev_samp%v", tt.values)
+	}
+}
+
+func TestNumericAggMerge(t *testing.T) {
+	text := nullDecimalText(t)
+
+	var a, b, all pgxdecimal.NumericAgg
+	for i, s := range []string{"1.5", "-2.25", "3", "10.125", "0.5"} {
+		v := decimal128.MustParse(s)
+		all.Add(v)
+		if i%2 == 0 {
+			a.Add(v)
+		} else {
+			b.AddNullDecimal(pgxdecimal.NullDecimal{Decimal: v, Valid: true})
+		}
+	}
+	b.AddNullDecimal(pgxdecimal.NullDecimal{})
+
+	var empty pgxdecimal.NumericAgg
+	a.Merge(&b)
+	a.Merge(&empty)
+
+	require.Equal(t, all.Count(), a.Count())
+	require.Equal(t, text(all.Sum()), text(a.Sum()))
+	require.Equal(t, text(all.VarSamp()), text(a.VarSamp()))
+}
+
+func TestNumericAggOverflow(t *testing.T) {
+	var agg pgxdecimal.NumericAgg
+	agg.Add(decimal128.MustParse("9999999999999999999999999999999999"))
+	agg.Add(decimal128.MustParse("0.1"))
+
+	_, err := agg.Sum()
+	require.EqualError(t, err, pgxdecimal.ErrNumericOverflow)
+}
+
+func TestNumericAggMatchServer(t *testing.T) {
+	defaultConnTestRunner.RunTest(context.Background(), t, func(ctx context.Context, t testing.TB, conn *pgx.Conn) {
+		for _, tt := range numericAggTests {
+			var result numericAggTest
+			err := conn.QueryRow(ctx, `select
+	coalesce(sum(x)::text, 'NULL'),
+	coalesce(avg(x)::text, 'NULL'),
+	coalesce(var_pop(x)::text, 'NULL'),
+	coalesce(var_samp(x)::text, 'NULL'),
+	coalesce(stddev_pop(x)::text, 'NULL'),
+	coalesce(stddev_samp(x)::text, 'NULL')
+from unnest($1::text[]::numeric[]) as t(x)`, tt.values).Scan(
+				&result.sum, &result.avg, &result.varPop, &result.varSamp, &result.stddevPop, &result.stddevSamp,
+			)
+			require.NoError(t, err)
+
+			result.values = tt.values
+			require.Equal(t, tt, result)
+		}
+	})
+}
@@ -0,0 +1,264 @@
+package decimal
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/bits"
+	"slices"
+
+	"github.com/ingothierack/decimal128"
+)
+
+// hashPartitionSeed is HASH_PARTITION_SEED from partition.h.
+const hashPartitionSeed = 0x7A5B22367996DCFD
+
+// HashNumeric returns the hash of d computed by PostgreSQL's hash_numeric, the
+// hash function of the numeric hash operator class. Equal values hash equally
+// regardless of scale, and NaN and infinities hash to 0.
+//
+// PostgreSQL hashes the in-memory representation of numeric digits, so the
+// result matches servers running on little-endian hardware.
+func HashNumeric(d decimal128.Decimal) int32 {
+	if d.IsNaN() || d.IsInf(0) {
+		return 0
+	}
+
+	weight, digits := newNumericVar(d).nbase()
+	if len(digits) == 0 {
+		return -1
+	}
+
+	return int32(hashBytes(numericDigitBytes(digits)) ^ uint32(weight))
+}
+
+// HashNumericExtended returns the 64-bit hash of d with the given seed,
+// computed by PostgreSQL's hash_numeric_extended. This is the hash used to
+// place rows in hash partitions.
+func HashNumericExtended(d decimal128.Decimal, seed int64) int64 {
+	if d.IsNaN() || d.IsInf(0) {
+		return seed
+	}
+
+	weight, digits := newNumericVar(d).nbase()
+	if len(digits) == 0 {
+		return seed - 1
+	}
+
+	return int64(hashBytesExtended(numericDigitBytes(digits), uint64(seed)) ^ uint64(int64(weight)))
+}
+
+// numericDigitBytes returns the base 10000 digits as int16 values in
+// little-endian byte order, as PostgreSQL stores them on such machines.
+func numericDigitBytes(digits []int) []byte {
+	b := make([]byte, 0, 2*len(digits))
+	for _, digit := range digits {
+		b = binary.LittleEndian.AppendUint16(b, uint16(digit))
+	}
+	return b
+}
+
+// NumericPartitionHash returns the hash that PostgreSQL computes for a row of
+// a table partitioned by hash on numeric columns, for the values of the
+// partition key columns in order. NULL values are ignored, as in
+// compute_partition_hash_value.
+func NumericPartitionHash(keys ...NullDecimal) uint64 {
+	var rowHash uint64
+	for _, key := range keys {
+		if key.Valid {
+			hash := uint64(HashNumericExtended(key.Decimal, hashPartitionSeed))
+			rowHash = hashCombine64(rowHash, hash)
+		}
+	}
+	return rowHash
+}
+
+// hashCombine64 is hash_combine64 from hashfn.h.
+func hashCombine64(a, b uint64) uint64 {
+	a ^= b + 0x49a0f4dd15e5a8e3 + (a << 54) + (a >> 7)
+	return a
+}
+
+// SatisfiesHashPartition reports whether a row with the given partition key
+// values belongs to the hash partition FOR VALUES WITH (MODULUS modulus,
+// REMAINDER remainder), like PostgreSQL's satisfies_hash_partition.
+func SatisfiesHashPartition(modulus, remainder int, keys ...NullDecimal) (bool, error) {
+	if err := checkHashPartitionBound(modulus, remainder); err != nil {
+		return false, err
+	}
+
+	return NumericPartitionHash(keys...)%uint64(modulus) == uint64(remainder), nil
+}
+
+func checkHashPartitionBound(modulus, remainder int) error {
+	switch {
+	case modulus <= 0:
+		return errors.New("modulus for hash partition must be an integer value greater than zero")
+	case remainder < 0:
+		return errors.New("remainder for hash partition must be an integer value greater than or equal to zero")
+	case remainder >= modulus:
+		return errors.New("remainder for hash partition must be less than modulus")
+	}
+	return nil
+}
+
+// HashPartition is a partition of a table partitioned by hash, created with
+// FOR VALUES WITH (MODULUS Modulus, REMAINDER Remainder).
+type HashPartition struct {
+	Name      string
+	Modulus   int
+	Remainder int
+}
+
+// HashPartitionRouter finds the partition of a hash partitioned table that a
+// row belongs to, without asking the server. The zero value has no
+// partitions.
+type HashPartitionRouter struct {
+	partitions []HashPartition
+
+	// indexes maps a row hash modulo the greatest modulus to the index of its
+	// partition, or -1, as in PartitionBoundInfo.
+	indexes []int
+}
+
+// NewHashPartitionRouter returns a router for the given partitions. As
+// PostgreSQL does when the partitions are created, it requires every modulus
+// to be a factor of the next larger one and the partitions not to overlap.
+func NewHashPartitionRouter(partitions ...HashPartition) (*HashPartitionRouter, error) {
+	r := &HashPartitionRouter{partitions: slices.Clone(partitions)}
+
+	greatest := 0
+	for _, p := range partitions {
+		if err := checkHashPartitionBound(p.Modulus, p.Remainder); err != nil {
+			return nil, fmt.Errorf("partition %q: %w", p.Name, err)
+		}
+		greatest = max(greatest, p.Modulus)
+	}
+
+	moduli := make([]int, 0, len(partitions))
+	for _, p := range partitions {
+		moduli = append(moduli, p.Modulus)
+	}
+	slices.Sort(moduli)
+	for i := 1; i < len(moduli); i++ {
+		if moduli[i]%moduli[i-1] != 0 {
+			return nil, errors.New("every hash partition modulus must be a factor of the next larger modulus")
+		}
+	}
+
+	r.indexes = make([]int, greatest)
+	for i := range r.indexes {
+		r.indexes[i] = -1
+	}
+
+	for i, p := range partitions {
+		for j := p.Remainder; j < greatest; j += p.Modulus {
+			if k := r.indexes[j]; k >= 0 {
+				return nil, fmt.Errorf("partition %q would overlap partition %q", p.Name, partitions[k].Name)
+			}
+			r.indexes[j] = i
+		}
+	}
+
+	return r, nil
+}
+
+// Route returns the partition that a row with the given partition key values
+// belongs to. It returns false if no partition accepts the row, in which case
+// PostgreSQL rejects it.
+func (r *HashPartitionRouter) Route(keys ...NullDecimal) (HashPartition, bool) {
+	if len(r.indexes) == 0 {
+		return HashPartition{}, false
+	}
+
+	i := r.indexes[NumericPartitionHash(keys...)%uint64(len(r.indexes))]
+	if i < 0 {
+		return HashPartition{}, false
+	}
+
+	return r.partitions[i], true
+}
+
+// hashBytes is hash_bytes from hashfn.c, Bob Jenkins' lookup3 hash as adapted
+// by PostgreSQL, for a little-endian machine.
+func hashBytes(k []byte) uint32 {
+	a, b, c := hashBytesState(k, 0)
+	_, _, c = hashFinal(a, b, c)
+	return c
+}
+
+// hashBytesExtended is hash_bytes_extended from hashfn.c.
+func hashBytesExtended(k []byte, seed uint64) uint64 {
+	a, b, c := hashBytesState(k, seed)
+	_, b, c = hashFinal(a, b, c)
+	return uint64(b)<<32 | uint64(c)
+}
+
+func hashBytesState(k []byte, seed uint64) (a, b, c uint32) {
+	a = 0x9e3779b9 + uint32(len(k)) + 3923095
+	b, c = a, a
+
+	// A non-zero seed is hashed as if it were a 12 byte chunk of the data.
+	if seed != 0 {
+		a += uint32(seed >> 32)
+		b += uint32(seed)
+		a, b, c = hashMix(a, b, c)
+	}
+
+	for len(k) >= 12 {
+		a += binary.LittleEndian.Uint32(k)
+		b += binary.LittleEndian.Uint32(k[4:])
+		c += binary.LittleEndian.Uint32(k[8:])
+		a, b, c = hashMix(a, b, c)
+		k = k[12:]
+	}
+
+	// The lowest byte of c is reserved for the length.
+	var tail [12]byte
+	copy(tail[:], k)
+	a += binary.LittleEndian.Uint32(tail[:])
+	b += binary.LittleEndian.Uint32(tail[4:])
+	c += binary.LittleEndian.Uint32(tail[8:]) << 8
+
+	return a, b, c
+}
+
+func hashMix(a, b, c uint32) (uint32, uint32, uint32) {
+	a -= c
+	a ^= bits.RotateLeft32(c, 4)
+	c += b
+	b -= a
+	b ^= bits.RotateLeft32(a, 6)
+	a += c
+	c -= b
+	c ^= bits.RotateLeft32(b, 8)
+	b += a
+	a -= c
+	a ^= bits.RotateLeft32(c, 16)
+	c += b
+	b -= a
+	b ^= bits.RotateLeft32(a, 19)
+	a += c
+	c -= b
+	c ^= bits.RotateLeft32(b, 4)
+	b += a
+	return a, b, c
+}
+
+func hashFinal(a, b, c uint32) (uint32, uint32, uint32) {
+	c ^= b
+	c -= bits.RotateLeft32(b, 14)
+	a ^= c
+	a -= bits.RotateLeft32(c, 11)
+	b ^= a
+	b -= bits.RotateLeft32(a, 25)
+	c ^= b
+	c -= bits.RotateLeft32(b, 16)
+	a ^= c
+	a -= bits.RotateLeft32(c, 4)
+	b ^= a
+	b -= bits.RotateLeft32(a, 14)
+	c ^= b
+	c -= bits.RotateLeft32(b, 24)
+	return a, b, c
+}
@@ -0,0 +1,186 @@
+package decimal_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ingothierack/decimal128"
+	pgxdecimal "github.com/ingothierack/pgx-woodsbury-decimal128"
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/require"
+)
+
+// hashNumericTests holds the results of PostgreSQL on little-endian hardware
+// for SELECT hash_numeric(x), hash_numeric_extended(x, 42), and the remainder
+// modulo 8 of the partition that x belongs to in a table partitioned by hash
+// (a, b), with b NULL and with b = 2.5. TestHashNumericMatchServer and
+// TestSatisfiesHashPartitionMatchServer check them against a live server.
+var hashNumericTests = []struct {
+	value       string
+	hash        int32
+	extended42  int64
+	remainder   int
+	remainder25 int
+}{
+	{"0", -1, 41, 7, 0},
+	{"1", 1324868424, -2742590637814259228, 1, 3},
+	{"-1", 1324868424, -2742590637814259228, 1, 3},
+	{"1.5", 692967894, -4021664855446081778, 6, 7},
+	{"10000", 1324868425, -2742590637814259227, 2, 0},
+	{"12345678.9", -1037569625, 3566015741047371008, 6, 5},
+	{"0.0001", -1324868425, 2742590637814259227, 4, 5},
+	{"0.00001234", 1263484006, 3157555329099454294, 0, 1},
+	{"-98765432109876543210.123456789", 1147183188, 7757196821152088020, 7, 2},
+	{"1e30", 1186574836, 8330202616442546920, 0, 0},
+	{"1e-30", -1186574837, -8330202616442546921, 5, 7},
+	{"NaN", 0, 42, 0, 7},
+	{"Infinity", 0, 42, 0, 7},
+	{"-Infinity", 0, 42, 0, 7},
+}
+
+func TestHashNumeric(t *testing.T) {
+	for _, tt := range hashNumericTests {
+		d := decimal128.MustParse(tt.value)
+		require.Equal(t, tt.hash, pgxdecimal.HashNumeric(d), tt.value)
+		require.Equal(t, tt.extended42, pgxdecimal.HashNumericExtended(d, 42), tt.value)
+	}
+
+	require.Equal(t, int32(-1), pgxdecimal.HashNumeric(decimal128.FromInt64(0)))
+	require.Equal(t, int32(0), pgxdecimal.HashNumeric(decimal128.NaN()))
+	require.Equal(t, int32(0), pgxdecimal.HashNumeric(decimal128.Inf(-1)))
+
+	require.Equal(t, int64(41), pgxdecimal.HashNumericExtended(decimal128.FromInt64(0), 42))
+	require.Equal(t, int64(42), pgxdecimal.HashNumericExtended(decimal128.NaN(), 42))
+
+	// The scale and the sign are not hashed.
+	for _, s := range []string{"1.0", "1.000", "-1"} {
+		require.Equal(t, pgxdecimal.HashNumeric(decimal128.FromInt64(1)), pgxdecimal.HashNumeric(decimal128.MustParse(s)), s)
+		require.Equal(t, pgxdecimal.HashNumericExtended(decimal128.FromInt64(1), 7), pgxdecimal.HashNumericExtended(decimal128.MustParse(s), 7), s)
+	}
+
+	// With a zero seed, the low 32 bits of the extended hash are the standard
+	// hash for values that are at least one.
+	for _, s := range []string{"1", "1.5", "10000", "12345678.9", "1e30"} {
+		d := decimal128.MustParse(s)
+		require.Equal(t, pgxdecimal.HashNumeric(d), int32(pgxdecimal.HashNumericExtended(d, 0)), s)
+	}
+}
+
+func TestSatisfiesHashPartition(t *testing.T) {
+	b := pgxdecimal.NullDecimal{Decimal: decimal128.MustParse("2.5"), Valid: true}
+	for _, tt := range hashNumericTests {
+		key := pgxdecimal.NullDecimal{Decimal: decimal128.MustParse(tt.value), Valid: true}
+
+		for remainder := range 8 {
+			ok, err := pgxdecimal.SatisfiesHashPartition(8, remainder, key, pgxdecimal.NullDecimal{})
+			require.NoError(t, err)
+			require.Equal(t, remainder == tt.remainder, ok, "%s remainder %d", tt.value, remainder)
+
+			ok, err = pgxdecimal.SatisfiesHashPartition(8, remainder, key, b)
+			require.NoError(t, err)
+			require.Equal(t, remainder == tt.remainder25, ok, "%s, 2.5 remainder %d", tt.value, remainder)
+		}
+	}
+
+	// NULL keys are ignored.
+	require.Equal(t, uint64(0), pgxdecimal.NumericPartitionHash(pgxdecimal.NullDecimal{}))
+	require.Equal(t,
+		pgxdecimal.NumericPartitionHash(pgxdecimal.NullDecimal{Decimal: decimal128.FromInt64(5), Valid: true}),
+		pgxdecimal.NumericPartitionHash(pgxdecimal.NullDecimal{}, pgxdecimal.NullDecimal{Decimal: decimal128.FromInt64(5), Valid: true}),
+	)
+
+	_, err := pgxdecimal.SatisfiesHashPartition(0, 0)
+	require.Error(t, err)
+	_, err = pgxdecimal.SatisfiesHashPartition(4, 4)
+	require.Error(t, err)
+}
+
+func TestHashPartitionRouter(t *testing.T) {
+	r, err := pgxdecimal.NewHashPartitionRouter(
+		pgxdecimal.HashPartition{Name: "p0", Modulus: 2, Remainder: 0},
+		pgxdecimal.HashPartition{Name: "p1", Modulus: 4, Remainder: 1},
+		pgxdecimal.HashPartition{Name: "p3", Modulus: 4, Remainder: 3},
+	)
+	require.NoError(t, err)
+
+	for _, tt := range hashNumericTests {
+		key := pgxdecimal.NullDecimal{Decimal: decimal128.MustParse(tt.value), Valid: true}
+
+		p, ok := r.Route(key)
+		require.True(t, ok, tt.value)
+
+		satisfies, err := pgxdecimal.SatisfiesHashPartition(p.Modulus, p.Remainder, key)
+		require.NoError(t, err)
+		require.True(t, satisfies, tt.value)
+	}
+
+	_, err = pgxdecimal.NewHashPartitionRouter(
+		pgxdecimal.HashPartition{Name: "p0", Modulus: 2, Remainder: 0},
+		pgxdecimal.HashPartition{Name: "p1", Modulus: 3, Remainder: 1},
+	)
+	require.EqualError(t, err, "every hash partition modulus must be a factor of the next larger modulus")
+
+	_, err = pgxdecimal.NewHashPartitionRouter(
+		pgxdecimal.HashPartition{Name: "p0", Modulus: 2, Remainder: 0},
+		pgxdecimal.HashPartition{Name: "p1", Modulus: 4, Remainder: 2},
+	)
+	require.EqualError(t, err, `partition "p1" would overlap partition "p0"`)
+
+	_, ok := (&pgxdecimal.HashPartitionRouter{}).Route()
+	require.False(t, ok)
+}
+
+func TestHashNumericMatchServer(t *testing.T) {
+	defaultConnTestRunner.RunTest(context.Background(), t, func(ctx context.Context, t testing.TB, conn *pgx.Conn) {
+		for _, tt := range hashNumericTests {
+			var hash int32
+			var extended int64
+			err := conn.QueryRow(ctx, "select hash_numeric($1::numeric), hash_numeric_extended($1::numeric, 42)", tt.value).Scan(&hash, &extended)
+			require.NoError(t, err)
+			require.Equal(t, tt.hash, hash, tt.value)
+			require.Equal(t, tt.extended42, extended, tt.value)
+		}
+	})
+}
+
+func TestSatisfiesHashPartitionMatchServer(t *testing.T) {
+	defaultConnTestRunner.RunTest(context.Background(), t, func(ctx context.Context, t testing.TB, conn *pgx.Conn) {
+		_, err := conn.Exec(ctx, "create temporary table hash_part (a numeric, b numeric) partition by hash (a, b)")
+		require.NoError(t, err)
+
+		for _, tt := range hashNumericTests {
+			var remainder, remainder25 bool
+			err := conn.QueryRow(ctx,
+				"select satisfies_hash_partition('hash_part'::regclass, 8, $1, $2::text::numeric, null::numeric), satisfies_hash_partition('hash_part'::regclass, 8, $3, $2::text::numeric, 2.5::numeric)",
+				tt.remainder, tt.value, tt.remainder25,
+			).Scan(&remainder, &remainder25)
+			require.NoError(t, err)
+			require.True(t, remainder, tt.value)
+			require.True(t, remainder25, tt.value)
+
+			for _, b := range []string{"", "0", "2.5"} {
+				keys := []pgxdecimal.NullDecimal{{Decimal: decimal128.MustParse(tt.value), Valid: true}, {}}
+				var bArg any
+				if b != "" {
+					keys[1] = pgxdecimal.NullDecimal{Decimal: decimal128.MustParse(b), Valid: true}
+					bArg = b
+				}
+
+				for _, modulus := range []int{1, 3, 8} {
+					for remainder := range modulus {
+						var expected bool
+						err := conn.QueryRow(ctx,
+							"select satisfies_hash_partition('hash_part'::regclass, $1, $2, $3::text::numeric, $4::text::numeric)",
+							modulus, remainder, tt.value, bArg,
+						).Scan(&expected)
+						require.NoError(t, err)
+
+						ok, err := pgxdecimal.SatisfiesHashPartition(modulus, remainder, keys...)
+						require.NoError(t, err)
+						require.Equal(t, expected, ok, "%s, %s modulus %d remainder %d", tt.value, b, modulus, remainder)
+					}
+				}
+			}
+		}
+	})
+}
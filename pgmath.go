@@ -0,0 +1,766 @@
+package decimal
+
+import (
+	"errors"
+	"math"
+	"math/big"
+
+	"github.com/ingothierack/decimal128"
+)
+
+// Constants from numeric.c. log10E is deliberately not math.Log10E, so that
+// result scales computed from it match PostgreSQL exactly.
+const (
+	numericMaxResultScale = 2000
+	log10E                = 0.434294481903252
+)
+
+const (
+	ErrSqrtOfNegative      = "cannot take square root of a negative number"
+	ErrLogOfNegative       = "cannot take logarithm of a negative number"
+	ErrLogOfZero           = "cannot take logarithm of zero"
+	ErrZeroToNegativePower = "zero raised to a negative power is undefined"
+	ErrComplexPower        = "a negative number raised to a non-integer power yields a complex result"
+)
+
+var (
+	errSqrtOfNegative      = errors.New(ErrSqrtOfNegative)
+	errLogOfNegative       = errors.New(ErrLogOfNegative)
+	errLogOfZero           = errors.New(ErrLogOfZero)
+	errZeroToNegativePower = errors.New(ErrZeroToNegativePower)
+	errComplexPower        = errors.New(ErrComplexPower)
+)
+
+var numericOne = numericVar{coef: big.NewInt(1)}
+
+// NumericRound rounds x to scale digits after the decimal point, half away
+// from zero, like PostgreSQL's round(numeric, int). A negative scale rounds to
+// the left of the decimal point. The result has max(scale, 0) digits after the
+// decimal point.
+func NumericRound(x decimal128.Decimal, scale int) (decimal128.Decimal, error) {
+	if x.IsNaN() || x.IsInf(0) {
+		return x, nil
+	}
+
+	return roundVar(newNumericVar(x), clampResultScale(scale), true).decimal()
+}
+
+// NumericTrunc truncates x towards zero to scale digits after the decimal
+// point, like PostgreSQL's trunc(numeric, int).
+func NumericTrunc(x decimal128.Decimal, scale int) (decimal128.Decimal, error) {
+	if x.IsNaN() || x.IsInf(0) {
+		return x, nil
+	}
+
+	return roundVar(newNumericVar(x), clampResultScale(scale), false).decimal()
+}
+
+// NumericCeil returns the smallest integer not less than x, like PostgreSQL's
+// ceil(numeric).
+func NumericCeil(x decimal128.Decimal) (decimal128.Decimal, error) {
+	if x.IsNaN() || x.IsInf(0) {
+		return x, nil
+	}
+
+	v := newNumericVar(x)
+	r := roundVar(v, 0, false)
+	if subVar(v, r).coef.Sign() > 0 {
+		r.coef.Add(r.coef, big.NewInt(1))
+	}
+
+	return r.decimal()
+}
+
+// NumericFloor returns the largest integer not greater than x, like
+// PostgreSQL's floor(numeric).
+func NumericFloor(x decimal128.Decimal) (decimal128.Decimal, error) {
+	if x.IsNaN() || x.IsInf(0) {
+		return x, nil
+	}
+
+	v := newNumericVar(x)
+	r := roundVar(v, 0, false)
+	if subVar(v, r).coef.Sign() < 0 {
+		r.coef.Sub(r.coef, big.NewInt(1))
+	}
+
+	return r.decimal()
+}
+
+// NumericSqrt returns the square root of x with the result scale PostgreSQL
+// uses for sqrt(numeric).
+func NumericSqrt(x decimal128.Decimal) (decimal128.Decimal, error) {
+	if x.IsNaN() || x.IsInf(1) {
+		return x, nil
+	}
+
+	if signOf(x) < 0 {
+		return decimal128.Decimal{}, errSqrtOfNegative
+	}
+
+	v := newNumericVar(x)
+
+	// The result has about half as many digits before the decimal point.
+	w, _ := v.weight()
+	sweight := (w+1)*decDigits/2 - 1
+
+	rscale := clampDisplayScale(max(numericMinSigDigits-sweight, v.scale))
+	return sqrtVar(v, rscale).decimal()
+}
+
+// NumericLn returns the natural logarithm of x with the result scale
+// PostgreSQL uses for ln(numeric).
+func NumericLn(x decimal128.Decimal) (decimal128.Decimal, error) {
+	if err := checkLogArg(x); err != nil {
+		return decimal128.Decimal{}, err
+	}
+
+	if x.IsNaN() || x.IsInf(1) {
+		return x, nil
+	}
+
+	v := newNumericVar(x)
+	rscale := clampDisplayScale(max(numericMinSigDigits-estimateLnDweight(v), v.scale))
+	return lnVar(v, rscale).decimal()
+}
+
+// NumericLog10 returns the base 10 logarithm of x, like PostgreSQL's
+// log(numeric).
+func NumericLog10(x decimal128.Decimal) (decimal128.Decimal, error) {
+	return NumericLog(decimal128.FromInt64(10), x)
+}
+
+// NumericLog returns the logarithm of x to the given base with the result
+// scale PostgreSQL uses for log(numeric, numeric).
+func NumericLog(base, x decimal128.Decimal) (decimal128.Decimal, error) {
+	if base.IsNaN() || x.IsNaN() {
+		return decimal128.NaN(), nil
+	}
+
+	if err := checkLogArg(base); err != nil {
+		return decimal128.Decimal{}, err
+	}
+	if err := checkLogArg(x); err != nil {
+		return decimal128.Decimal{}, err
+	}
+
+	switch {
+	case base.IsInf(0) && x.IsInf(0):
+		return decimal128.NaN(), nil
+	case base.IsInf(0):
+		return decimal128.Decimal{}, nil
+	case x.IsInf(0):
+		return x, nil
+	}
+
+	b, v := newNumericVar(base), newNumericVar(x)
+
+	dweight := estimateLnDweight(v) - estimateLnDweight(b)
+	rscale := clampDisplayScale(max(numericMinSigDigits-dweight, b.scale, v.scale))
+
+	lnBase := lnFloat(b, floatPrec(rscale+max(dweight, 0)))
+	if lnBase.Sign() == 0 {
+		return decimal128.Decimal{}, errDivisionByZero
+	}
+
+	lnX := lnFloat(v, floatPrec(rscale+max(dweight, 0)))
+	return floatToVar(lnX.Quo(lnX, lnBase), rscale).decimal()
+}
+
+// NumericExp returns e raised to the power x with the result scale PostgreSQL
+// uses for exp(numeric).
+func NumericExp(x decimal128.Decimal) (decimal128.Decimal, error) {
+	if x.IsNaN() || x.IsInf(1) {
+		return x, nil
+	}
+
+	if x.IsInf(-1) {
+		return decimal128.Decimal{}, nil
+	}
+
+	v := newNumericVar(x)
+
+	// The decimal weight of the result is about x * log10(e).
+	val := x.Float64() * log10E
+	val = min(max(val, -numericMaxResultScale), numericMaxResultScale)
+
+	rscale := clampDisplayScale(max(numericMinSigDigits-int(val), v.scale))
+
+	r, err := expVar(v, rscale)
+	if err != nil {
+		return decimal128.Decimal{}, err
+	}
+
+	return r.decimal()
+}
+
+// NumericPower returns x raised to the power y with the result scale
+// PostgreSQL uses for power(numeric, numeric). Special values follow the
+// POSIX rules for pow, as in PostgreSQL.
+func NumericPower(x, y decimal128.Decimal) (decimal128.Decimal, error) {
+	if x.IsNaN() || x.IsInf(0) || y.IsNaN() || y.IsInf(0) {
+		return powerSpecial(x, y)
+	}
+
+	if x.IsZero() && signOf(y) < 0 {
+		return decimal128.Decimal{}, errZeroToNegativePower
+	}
+
+	base, exp := newNumericVar(x), newNumericVar(y)
+
+	if e := intPart(exp); isIntegral(exp) && e.IsInt64() && e.Int64() >= math.MinInt32 && e.Int64() <= math.MaxInt32 {
+		r, err := powerVarInt(base, int(e.Int64()), exp.scale)
+		if err != nil {
+			return decimal128.Decimal{}, err
+		}
+		return r.decimal()
+	}
+
+	r, err := powerVar(base, exp)
+	if err != nil {
+		return decimal128.Decimal{}, err
+	}
+
+	return r.decimal()
+}
+
+// powerSpecial handles NaN and infinite operands of NumericPower.
+func powerSpecial(x, y decimal128.Decimal) (decimal128.Decimal, error) {
+	one := decimal128.FromInt64(1)
+
+	// NaN ^ 0 and 1 ^ NaN are 1, any other NaN operand yields NaN.
+	if x.IsNaN() {
+		if !y.IsNaN() && !y.IsInf(0) && y.IsZero() {
+			return one, nil
+		}
+		return decimal128.NaN(), nil
+	}
+	if y.IsNaN() {
+		if !x.IsInf(0) && x.Equal(one) {
+			return one, nil
+		}
+		return decimal128.NaN(), nil
+	}
+
+	sx, sy := signOf(x), signOf(y)
+	if sx == 0 && sy < 0 {
+		return decimal128.Decimal{}, errZeroToNegativePower
+	}
+	if sx < 0 && !y.IsInf(0) && !isIntegral(newNumericVar(y)) {
+		return decimal128.Decimal{}, errComplexPower
+	}
+
+	switch {
+	case !x.IsInf(0) && x.Equal(one), sy == 0:
+		return one, nil
+	case sx == 0:
+		return decimal128.Decimal{}, nil
+	}
+
+	if y.IsInf(0) {
+		if !x.IsInf(0) && x.Equal(one.Neg()) {
+			return one, nil
+		}
+
+		absGreaterThanOne := x.IsInf(0) || decimal128.Abs(x).Cmp(one).Greater()
+		if absGreaterThanOne == (sy > 0) {
+			return decimal128.Inf(1), nil
+		}
+		return decimal128.Decimal{}, nil
+	}
+
+	// x is infinite and y is finite and non-zero.
+	if x.IsInf(1) || sy < 0 {
+		if sy > 0 {
+			return decimal128.Inf(1), nil
+		}
+		return decimal128.Decimal{}, nil
+	}
+
+	// -Infinity raised to an odd integer is -Infinity.
+	v := newNumericVar(y)
+	if isIntegral(v) && intPart(v).Bit(0) == 1 {
+		return decimal128.Inf(-1), nil
+	}
+
+	return decimal128.Inf(1), nil
+}
+
+// NumericGcd returns the greatest common divisor of a and b, like
+// PostgreSQL's gcd(numeric, numeric). The result is never negative.
+func NumericGcd(a, b decimal128.Decimal) (decimal128.Decimal, error) {
+	if a.IsNaN() || a.IsInf(0) || b.IsNaN() || b.IsInf(0) {
+		return decimal128.NaN(), nil
+	}
+
+	return gcdVar(newNumericVar(a), newNumericVar(b)).decimal()
+}
+
+// NumericLcm returns the least common multiple of a and b, like PostgreSQL's
+// lcm(numeric, numeric). The result is zero if either value is zero.
+func NumericLcm(a, b decimal128.Decimal) (decimal128.Decimal, error) {
+	if a.IsNaN() || a.IsInf(0) || b.IsNaN() || b.IsInf(0) {
+		return decimal128.NaN(), nil
+	}
+
+	x, y := newNumericVar(a), newNumericVar(b)
+	scale := max(x.scale, y.scale)
+	if x.coef.Sign() == 0 || y.coef.Sign() == 0 {
+		return numericVar{coef: new(big.Int), scale: scale}.decimal()
+	}
+
+	gcd := gcdVar(x, y)
+	r := mulVar(divVar(x, gcd, 0, false), y)
+	r.coef.Abs(r.coef)
+	return roundVar(r, scale, false).decimal()
+}
+
+// NumericScale returns the number of digits after the decimal point of x, like
+// PostgreSQL's scale(numeric). The boolean result is false, like scale
+// returning NULL, if x is NaN or infinite. Zero has no scale in decimal128, so
+// the scale of any zero is 0.
+func NumericScale(x decimal128.Decimal) (int, bool) {
+	if x.IsNaN() || x.IsInf(0) {
+		return 0, false
+	}
+
+	return newNumericVar(x).scale, true
+}
+
+// NumericMinScale returns the smallest scale that represents x exactly, like
+// PostgreSQL's min_scale(numeric). The boolean result is false if x is NaN or
+// infinite.
+func NumericMinScale(x decimal128.Decimal) (int, bool) {
+	if x.IsNaN() || x.IsInf(0) {
+		return 0, false
+	}
+
+	return trimScale(newNumericVar(x)).scale, true
+}
+
+// NumericTrimScale returns x with trailing zeros after the decimal point
+// removed, like PostgreSQL's trim_scale(numeric).
+func NumericTrimScale(x decimal128.Decimal) (decimal128.Decimal, error) {
+	if x.IsNaN() || x.IsInf(0) {
+		return x, nil
+	}
+
+	return trimScale(newNumericVar(x)).decimal()
+}
+
+func clampResultScale(scale int) int {
+	return min(max(scale, -numericMaxResultScale), numericMaxResultScale)
+}
+
+func clampDisplayScale(scale int) int {
+	return min(max(scale, numericMinDisplayScale), numericMaxDisplayScale)
+}
+
+// roundVar rounds v to scale digits after the decimal point, half away from
+// zero if round is set and towards zero otherwise. Like round_var and
+// trunc_var, a negative scale yields a result with scale 0.
+func roundVar(v numericVar, scale int, round bool) numericVar {
+	r := divVar(v, numericOne, scale, round)
+	if scale < 0 {
+		r.coef.Mul(r.coef, pow10(-scale))
+		r.scale = 0
+	}
+
+	return r
+}
+
+func trimScale(v numericVar) numericVar {
+	coef := new(big.Int).Set(v.coef)
+	scale := v.scale
+
+	q, r := new(big.Int), new(big.Int)
+	for scale > 0 && coef.Sign() != 0 {
+		q.QuoRem(coef, bigTen, r)
+		if r.Sign() != 0 {
+			break
+		}
+		coef.Set(q)
+		scale--
+	}
+
+	if coef.Sign() == 0 {
+		scale = 0
+	}
+
+	return numericVar{coef: coef, scale: scale}
+}
+
+// intPart returns the integer part of v.
+func intPart(v numericVar) *big.Int {
+	return new(big.Int).Quo(v.coef, pow10(v.scale))
+}
+
+// isIntegral reports whether v has no fractional part.
+func isIntegral(v numericVar) bool {
+	return new(big.Int).Rem(v.coef, pow10(v.scale)).Sign() == 0
+}
+
+func gcdVar(a, b numericVar) numericVar {
+	scale := max(a.scale, b.scale)
+	x := new(big.Int).Mul(a.coef, pow10(scale-a.scale))
+	y := new(big.Int).Mul(b.coef, pow10(scale-b.scale))
+
+	return numericVar{coef: new(big.Int).GCD(nil, nil, x.Abs(x), y.Abs(y)), scale: scale}
+}
+
+func checkLogArg(x decimal128.Decimal) error {
+	if x.IsNaN() {
+		return nil
+	}
+
+	switch signOf(x) {
+	case -1:
+		return errLogOfNegative
+	case 0:
+		return errLogOfZero
+	}
+
+	return nil
+}
+
+// estimateLnDweight is estimate_ln_dweight from numeric.c. It returns the
+// approximate decimal weight of ln(v).
+func estimateLnDweight(v numericVar) int {
+	if v.coef.Sign() <= 0 {
+		return 0
+	}
+
+	lower := numericVar{coef: big.NewInt(9), scale: 1}
+	upper := numericVar{coef: big.NewInt(11), scale: 1}
+	if subVar(v, lower).coef.Sign() >= 0 && subVar(v, upper).coef.Sign() <= 0 {
+		// ln(1 + x) is about x, so use the weight of the leading digit of x.
+		x := subVar(v, numericOne)
+		if x.coef.Sign() == 0 {
+			return 0
+		}
+
+		return len(new(big.Int).Abs(x.coef).String()) - 1 - x.scale
+	}
+
+	w, digits := v.nbase()
+	f := float64(digits[0])
+	dweight := w * decDigits
+	if len(digits) > 1 {
+		f = f*10000 + float64(digits[1])
+		dweight -= decDigits
+	}
+
+	ln := math.Log(f) + float64(dweight)*math.Ln10
+	return int(math.Log10(math.Abs(ln)))
+}
+
+// sqrtVar returns the square root of v rounded half away from zero to rscale
+// digits after the decimal point. v must not be negative and rscale must not
+// be less than v.scale.
+func sqrtVar(v numericVar, rscale int) numericVar {
+	n := new(big.Int).Mul(v.coef, pow10(2*rscale-v.scale))
+
+	// floor(sqrt(n) + 1/2) == floor((floor(2 * sqrt(n)) + 1) / 2)
+	s := new(big.Int).Sqrt(n.Lsh(n, 2))
+	s.Add(s, big.NewInt(1)).Rsh(s, 1)
+
+	return numericVar{coef: s, scale: rscale}
+}
+
+// lnVar returns ln(v) rounded to rscale digits after the decimal point. v must
+// be positive.
+func lnVar(v numericVar, rscale int) numericVar {
+	// |ln(v)| has at most five digits before the decimal point.
+	return floatToVar(lnFloat(v, floatPrec(rscale+5)), rscale)
+}
+
+// expVar returns e^v rounded to rscale digits after the decimal point.
+func expVar(v numericVar, rscale int) (numericVar, error) {
+	val := new(big.Float).SetInt(v.coef)
+	val.Quo(val, new(big.Float).SetInt(pow10(v.scale)))
+	dweight, _ := val.Float64()
+	dweight *= log10E
+
+	if dweight > -decimal128MinExponent {
+		return numericVar{}, errNumericOverflow
+	}
+
+	// The result rounds to zero.
+	if dweight < float64(-rscale-2) {
+		return numericVar{coef: new(big.Int), scale: rscale}, nil
+	}
+
+	prec := floatPrec(rscale + int(math.Ceil(dweight)) + 1)
+
+	x := new(big.Float).SetPrec(prec + 16).SetInt(v.coef)
+	x.Quo(x, new(big.Float).SetInt(pow10(v.scale)))
+
+	return floatToVar(expFloat(x, prec), rscale), nil
+}
+
+// decimal128MinExponent bounds the decimal weight of any finite
+// decimal128.Decimal, so results with a larger weight cannot be represented.
+const decimal128MinExponent = -6176
+
+// powerVarInt is power_var_int from numeric.c. It computes base^exp by
+// repeated squaring, keeping just enough digits in the intermediate products
+// to round the final result correctly.
+func powerVarInt(base numericVar, exp int, expScale int) (numericVar, error) {
+	// Estimate the decimal weight of the result from base ~= f * 10^p.
+	var f float64
+	if base.coef.Sign() != 0 {
+		w, digits := base.nbase()
+		f = float64(digits[0])
+		p := w * decDigits
+		for i := 1; i < len(digits) && i*decDigits < 16; i++ {
+			f = f*10000 + float64(digits[i])
+			p -= decDigits
+		}
+
+		f = float64(exp) * (math.Log10(f) + float64(p))
+	}
+
+	if f > -decimal128MinExponent {
+		return numericVar{}, errNumericOverflow
+	}
+
+	if f+1 < -numericMaxDisplayScale {
+		return numericVar{coef: new(big.Int), scale: numericMaxDisplayScale}, nil
+	}
+
+	rscale := clampDisplayScale(max(numericMinSigDigits-int(f), base.scale, expScale))
+
+	switch exp {
+	case 0:
+		return numericVar{coef: pow10(rscale), scale: rscale}, nil
+	case 1:
+		return roundVar(base, rscale, true), nil
+	case -1:
+		return divVar(numericOne, base, rscale, true), nil
+	case 2:
+		return roundVar(mulVar(base, base), rscale, true), nil
+	}
+
+	if base.coef.Sign() == 0 {
+		return numericVar{coef: new(big.Int), scale: rscale}, nil
+	}
+
+	// Significant digits needed in each product, allowing for the rounding
+	// errors of the multiplications.
+	sigDigits := 1 + rscale + int(f)
+	sigDigits += int(math.Log(math.Abs(float64(exp)))) + 8
+
+	neg := exp < 0
+	mask := uint32(exp)
+	if neg {
+		mask = uint32(-int64(exp))
+	}
+
+	prod := base
+	result := numericOne
+	if mask&1 != 0 {
+		result = base
+	}
+
+	for mask >>= 1; mask > 0; mask >>= 1 {
+		pw, _ := prod.weight()
+		localRscale := min(sigDigits-2*pw*decDigits, 2*prod.scale)
+		prod = roundVar(mulVar(prod, prod), max(localRscale, numericMinDisplayScale), true)
+
+		if mask&1 != 0 {
+			rw, _ := result.weight()
+			pw, _ = prod.weight()
+			localRscale = min(sigDigits-(pw+rw)*decDigits, prod.scale+result.scale)
+			result = roundVar(mulVar(prod, result), max(localRscale, numericMinDisplayScale), true)
+		}
+
+		// Past this weight the result cannot be represented, or rounds to
+		// zero if exp is negative.
+		pw, _ = prod.weight()
+		rw, _ := result.weight()
+		if max(pw, rw) > -decimal128MinExponent/decDigits {
+			if !neg {
+				return numericVar{}, errNumericOverflow
+			}
+			return numericVar{coef: new(big.Int), scale: rscale}, nil
+		}
+	}
+
+	if neg {
+		return divVar(numericOne, result, rscale, true), nil
+	}
+
+	return roundVar(result, rscale, true), nil
+}
+
+// powerVar is the non-integer exponent case of power_var from numeric.c,
+// computing base^exp as exp(exp * ln(base)).
+func powerVar(base, exp numericVar) (numericVar, error) {
+	if base.coef.Sign() == 0 {
+		return numericVar{coef: new(big.Int), scale: numericMinSigDigits}, nil
+	}
+
+	neg := false
+	if base.coef.Sign() < 0 {
+		if !isIntegral(exp) {
+			return numericVar{}, errComplexPower
+		}
+
+		e := intPart(exp)
+		neg = e.Abs(e).Bit(0) == 1
+		base = numericVar{coef: new(big.Int).Neg(base.coef), scale: base.scale}
+	}
+
+	// Estimate the weight of the result from a low precision calculation.
+	lnDweight := estimateLnDweight(base)
+	localRscale := max(8-lnDweight, numericMinDisplayScale)
+
+	lnNum := roundVar(mulVar(lnVar(base, localRscale), exp), localRscale, true)
+	val := new(big.Float).SetInt(lnNum.coef)
+	val.Quo(val, new(big.Float).SetInt(pow10(lnNum.scale)))
+	fval, _ := val.Float64()
+
+	if math.Abs(fval) > numericMaxResultScale*3.01 {
+		if fval > 0 {
+			return numericVar{}, errNumericOverflow
+		}
+		return numericVar{coef: new(big.Int), scale: numericMaxDisplayScale}, nil
+	}
+
+	fval *= log10E
+
+	rscale := clampDisplayScale(max(numericMinSigDigits-int(fval), base.scale, exp.scale))
+	sigDigits := max(rscale+int(fval), 0)
+
+	// Do the real calculation.
+	localRscale = max(sigDigits-lnDweight+8, numericMinDisplayScale)
+	lnNum = roundVar(mulVar(lnVar(base, localRscale), exp), localRscale, true)
+
+	r, err := expVar(lnNum, rscale)
+	if err != nil {
+		return numericVar{}, err
+	}
+
+	if neg {
+		r.coef.Neg(r.coef)
+	}
+
+	return r, nil
+}
+
+// floatPrec returns the big.Float precision needed for digits significant
+// decimal digits, with guard bits to spare.
+func floatPrec(digits int) uint {
+	return uint(max(digits, 0)+20)*10/3 + 64
+}
+
+// floatToVar rounds f half away from zero to rscale digits after the decimal
+// point.
+func floatToVar(f *big.Float, rscale int) numericVar {
+	t := new(big.Float).SetPrec(f.Prec() + uint(rscale)*10/3 + 64)
+	t.Mul(f, new(big.Float).SetInt(pow10(rscale)))
+
+	half := big.NewFloat(0.5)
+	if t.Signbit() {
+		t.Sub(t, half)
+	} else {
+		t.Add(t, half)
+	}
+
+	coef, _ := t.Int(nil)
+	return numericVar{coef: coef, scale: rscale}
+}
+
+// lnFloat returns ln(v) with prec bits of precision. v must be positive.
+func lnFloat(v numericVar, prec uint) *big.Float {
+	wp := prec + 64
+	one := pow10(v.scale)
+
+	// Near 1, use ln(v) = 2 * atanh((v - 1) / (v + 1)) computed from the exact
+	// numerator and denominator, so that no precision is lost to cancellation.
+	twice := new(big.Int).Lsh(v.coef, 1)
+	if twice.Cmp(one) >= 0 && v.coef.Cmp(new(big.Int).Lsh(one, 1)) <= 0 {
+		num := new(big.Float).SetPrec(wp).SetInt(new(big.Int).Sub(v.coef, one))
+		den := new(big.Float).SetPrec(wp).SetInt(new(big.Int).Add(v.coef, one))
+		z := atanhFloat(num.Quo(num, den), wp)
+		return z.Mul(z, big.NewFloat(2)).SetPrec(prec)
+	}
+
+	// Otherwise ln(v) = ln(m) + e * ln(2) with v = m * 2^e and 0.5 <= m < 1.
+	x := new(big.Float).SetPrec(wp).SetInt(v.coef)
+	x.Quo(x, new(big.Float).SetPrec(wp).SetInt(one))
+
+	m := new(big.Float).SetPrec(wp)
+	e := x.MantExp(m)
+
+	num := new(big.Float).SetPrec(wp).Sub(m, big.NewFloat(1))
+	den := new(big.Float).SetPrec(wp).Add(m, big.NewFloat(1))
+	r := atanhFloat(num.Quo(num, den), wp)
+	r.Mul(r, big.NewFloat(2))
+
+	ln2 := ln2Float(wp)
+	r.Add(r, ln2.Mul(ln2, new(big.Float).SetInt64(int64(e))))
+	return r.SetPrec(prec)
+}
+
+// expFloat returns e^x with prec bits of precision.
+func expFloat(x *big.Float, prec uint) *big.Float {
+	const halvings = 16
+	wp := prec + 64 + halvings
+
+	// e^x = 2^n * e^r with r = x - n * ln(2) and |r| <= ln(2) / 2.
+	ln2 := ln2Float(wp)
+	q, _ := new(big.Float).SetPrec(wp).Quo(x, ln2).Float64()
+	n := int(math.Round(q))
+
+	r := new(big.Float).SetPrec(wp).Mul(ln2, new(big.Float).SetInt64(int64(n)))
+	r.Sub(x, r)
+	r.SetMantExp(r, -halvings)
+
+	// Taylor series for e^(r / 2^halvings), then square it back up.
+	sum := new(big.Float).SetPrec(wp).SetInt64(1)
+	term := new(big.Float).SetPrec(wp).SetInt64(1)
+	for i := int64(1); ; i++ {
+		term.Mul(term, r)
+		term.Quo(term, new(big.Float).SetInt64(i))
+		if term.Sign() == 0 || term.MantExp(nil) < sum.MantExp(nil)-int(wp) {
+			break
+		}
+		sum.Add(sum, term)
+	}
+
+	for range halvings {
+		sum.Mul(sum, sum)
+	}
+
+	return sum.SetMantExp(sum, n).SetPrec(prec)
+}
+
+// atanhFloat returns atanh(z) for |z| <= 1/3 with prec bits of precision.
+func atanhFloat(z *big.Float, prec uint) *big.Float {
+	sum := new(big.Float).SetPrec(prec).Set(z)
+	if z.Sign() == 0 {
+		return sum
+	}
+
+	z2 := new(big.Float).SetPrec(prec).Mul(z, z)
+	pow := new(big.Float).SetPrec(prec).Set(z)
+	term := new(big.Float).SetPrec(prec)
+	for k := int64(3); ; k += 2 {
+		pow.Mul(pow, z2)
+		term.Quo(pow, new(big.Float).SetInt64(k))
+		if term.Sign() == 0 || term.MantExp(nil) < sum.MantExp(nil)-int(prec) {
+			break
+		}
+		sum.Add(sum, term)
+	}
+
+	return sum
+}
+
+// ln2Float returns ln(2) = 2 * atanh(1/3) with prec bits of precision.
+func ln2Float(prec uint) *big.Float {
+	third := new(big.Float).SetPrec(prec).Quo(big.NewFloat(1), big.NewFloat(3))
+	r := atanhFloat(third, prec)
+	return r.Mul(r, big.NewFloat(2))
+}
@@ -0,0 +1,205 @@
+package decimal_test
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/ingothierack/decimal128"
+	pgxdecimal "github.com/ingothierack/pgx-woodsbury-decimal128"
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/require"
+)
+
+type numericFuncTest struct {
+	fn       string
+	args     []string
+	expected string
+}
+
+// numericFuncTests holds the text output of PostgreSQL for SELECT fn(args...)
+// with the arguments cast to numeric, except for the scale argument of round
+// and trunc. TestNumericFuncsMatchServer checks them against a live server.
+var numericFuncTests = []numericFuncTest{
+	{"round", []string{"42.4382", "2"}, "42.44"},
+	{"round", []string{"-42.5", "0"}, "-43"},
+	{"round", []string{"1234.5678", "-2"}, "1200"},
+	{"round", []string{"1.5", "3"}, "1.500"},
+	{"round", []string{"Infinity", "2"}, "Infinity"},
+	{"trunc", []string{"42.4382", "2"}, "42.43"},
+	{"trunc", []string{"-42.8", "0"}, "-42"},
+	{"trunc", []string{"1299", "-2"}, "1200"},
+	{"ceil", []string{"-42.8"}, "-42"},
+	{"ceil", []string{"42.2"}, "43"},
+	{"ceil", []string{"-0.5"}, "0"},
+	{"floor", []string{"-42.8"}, "-43"},
+	{"floor", []string{"42.8"}, "42"},
+
+	{"sqrt", []string{"2"}, "1.414213562373095"},
+	{"sqrt", []string{"16"}, "4.000000000000000"},
+	{"sqrt", []string{"0.0001"}, "0.01000000000000000"},
+	{"sqrt", []string{"123456789012345678"}, "351364182.8820144"},
+	{"sqrt", []string{"Infinity"}, "Infinity"},
+
+	{"ln", []string{"2.0"}, "0.6931471805599453"},
+	{"ln", []string{"10"}, "2.3025850929940457"},
+	{"ln", []string{"0.001"}, "-6.9077552789821371"},
+	{"ln", []string{"1.000001"}, "0.0000009999995000003333"},
+	{"ln", []string{"1e100"}, "230.25850929940457"},
+	{"log", []string{"100"}, "2.0000000000000000"},
+	{"log", []string{"2.0", "64.0"}, "6.0000000000000000"},
+	{"log", []string{"Infinity", "5"}, "0"},
+
+	{"exp", []string{"1.0"}, "2.7182818284590452"},
+	{"exp", []string{"0.0"}, "1.0000000000000000"},
+	{"exp", []string{"-10"}, "0.00004539992976248485"},
+	{"exp", []string{"50"}, "5184705528587072464087"},
+	{"exp", []string{"-Infinity"}, "0"},
+
+	{"power", []string{"2", "10"}, "1024.0000000000000"},
+	{"power", []string{"9", "3"}, "729.00000000000000"},
+	{"power", []string{"2", "-2"}, "0.2500000000000000"},
+	{"power", []string{"-2", "3"}, "-8.0000000000000000"},
+	{"power", []string{"0.5", "24"}, "0.00000005960464477539063"},
+	{"power", []string{"1.05", "30"}, "4.3219423751506620"},
+	{"power", []string{"2", "0.5"}, "1.4142135623730950"},
+	{"power", []string{"1.05", "30.5"}, "4.4286730731483322"},
+	{"power", []string{"-1", "12345678901"}, "-1.0000000000000000"},
+	{"power", []string{"NaN", "0"}, "1"},
+	{"power", []string{"0.5", "-Infinity"}, "Infinity"},
+	{"power", []string{"-Infinity", "3"}, "-Infinity"},
+
+	{"mod", []string{"-7", "3"}, "-1"},
+
+	{"gcd", []string{"1071", "-462"}, "21"},
+	{"gcd", []string{"0.5", "0.15"}, "0.05"},
+	{"lcm", []string{"1071", "462"}, "23562"},
+	{"lcm", []string{"0.5", "-0.15"}, "1.50"},
+	{"lcm", []string{"NaN", "2"}, "NaN"},
+
+	{"trim_scale", []string{"8.4100"}, "8.41"},
+	{"trim_scale", []string{"100"}, "100"},
+}
+
+func numericFunc(fn string, args []string) (decimal128.Decimal, error) {
+	x := decimal128.MustParse(args[0])
+
+	var y decimal128.Decimal
+	if len(args) > 1 {
+		y = decimal128.MustParse(args[1])
+	}
+
+	switch fn {
+	case "round", "trunc":
+		scale, err := strconv.Atoi(args[1])
+		if err != nil {
+			panic(err)
+		}
+		if fn == "round" {
+			return pgxdecimal.NumericRound(x, scale)
+		}
+		return pgxdecimal.NumericTrunc(x, scale)
+	case "ceil":
+		return pgxdecimal.NumericCeil(x)
+	case "floor":
+		return pgxdecimal.NumericFloor(x)
+	case "sqrt":
+		return pgxdecimal.NumericSqrt(x)
+	case "ln":
+		return pgxdecimal.NumericLn(x)
+	case "log":
+		if len(args) == 1 {
+			return pgxdecimal.NumericLog10(x)
+		}
+		return pgxdecimal.NumericLog(x, y)
+	case "exp":
+		return pgxdecimal.NumericExp(x)
+	case "power":
+		return pgxdecimal.NumericPower(x, y)
+	case "mod":
+		return pgxdecimal.NumericMod(x, y)
+	case "gcd":
+		return pgxdecimal.NumericGcd(x, y)
+	case "lcm":
+		return pgxdecimal.NumericLcm(x, y)
+	case "trim_scale":
+		return pgxdecimal.NumericTrimScale(x)
+	}
+
+	panic("unknown function " + fn)
+}
+
+func TestNumericFuncs(t *testing.T) {
+	for _, tt := range numericFuncTests {
+		result, err := numericFunc(tt.fn, tt.args)
+		require.NoError(t, err, "%s%v", tt.fn, tt.args)
+		require.Equal(t, tt.expected, pgxdecimal.Decimal(result).String(), "%s%v", tt.fn, tt.args)
+	}
+}
+
+func TestNumericFuncsErrors(t *testing.T) {
+	for _, tt := range []struct {
+		fn   string
+		args []string
+		err  string
+	}{
+		{"sqrt", []string{"-1"}, pgxdecimal.ErrSqrtOfNegative},
+		{"sqrt", []string{"-Infinity"}, pgxdecimal.ErrSqrtOfNegative},
+		{"ln", []string{"0"}, pgxdecimal.ErrLogOfZero},
+		{"ln", []string{"-1"}, pgxdecimal.ErrLogOfNegative},
+		{"log", []string{"1", "10"}, pgxdecimal.ErrDivisionByZero},
+		{"log", []string{"10", "-Infinity"}, pgxdecimal.ErrLogOfNegative},
+		{"power", []string{"0", "-1"}, pgxdecimal.ErrZeroToNegativePower},
+		{"power", []string{"-2", "0.5"}, pgxdecimal.ErrComplexPower},
+		{"power", []string{"-8", "1e10"}, pgxdecimal.ErrNumericOverflow},
+		{"exp", []string{"100"}, pgxdecimal.ErrNumericOverflow},
+	} {
+		_, err := numericFunc(tt.fn, tt.args)
+		require.EqualError(t, err, tt.err, "%s%v", tt.fn, tt.args)
+	}
+}
+
+func TestNumericScale(t *testing.T) {
+	scale, ok := pgxdecimal.NumericScale(decimal128.MustParse("8.4100"))
+	require.True(t, ok)
+	require.Equal(t, 4, scale)
+
+	scale, ok = pgxdecimal.NumericMinScale(decimal128.MustParse("8.4100"))
+	require.True(t, ok)
+	require.Equal(t, 2, scale)
+
+	scale, ok = pgxdecimal.NumericMinScale(decimal128.MustParse("1200"))
+	require.True(t, ok)
+	require.Equal(t, 0, scale)
+
+	_, ok = pgxdecimal.NumericScale(decimal128.NaN())
+	require.False(t, ok)
+
+	_, ok = pgxdecimal.NumericMinScale(decimal128.Inf(1))
+	require.False(t, ok)
+}
+
+func TestNumericFuncsMatchServer(t *testing.T) {
+	defaultConnTestRunner.RunTest(context.Background(), t, func(ctx context.Context, t testing.TB, conn *pgx.Conn) {
+		for _, tt := range numericFuncTests {
+			params := make([]string, len(tt.args))
+			args := make([]any, len(tt.args))
+			for i, a := range tt.args {
+				params[i] = fmt.Sprintf("$%d::numeric", i+1)
+				if i == 1 && (tt.fn == "round" || tt.fn == "trunc") {
+					params[i] = fmt.Sprintf("$%d::int", i+1)
+				}
+				args[i] = a
+			}
+
+			sql := fmt.Sprintf("select %s(%s)::text", tt.fn, strings.Join(params, ", "))
+
+			var result string
+			err := conn.QueryRow(ctx, sql, args...).Scan(&result)
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, result, "%s%v", tt.fn, tt.args)
+		}
+	})
+}
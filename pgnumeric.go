@@ -0,0 +1,321 @@
+package decimal
+
+import (
+	"errors"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/ingothierack/decimal128"
+)
+
+// Constants from PostgreSQL's numeric.c.
+const (
+	decDigits              = 4
+	numericMinSigDigits    = 16
+	numericMinDisplayScale = 0
+	numericMaxDisplayScale = 1000
+)
+
+const ErrNumericOverflow = "numeric result does not fit in decimal128"
+
+var errNumericOverflow = errors.New(ErrNumericOverflow)
+
+var (
+	bigTen         = big.NewInt(10)
+	maxCoefficient = new(big.Int).Sub(new(big.Int).Exp(bigTen, big.NewInt(34), nil), big.NewInt(1))
+)
+
+// numericVar is a finite value coef * 10^-scale with scale >= 0. It plays the
+// role of PostgreSQL's NumericVar, with scale as its dscale.
+type numericVar struct {
+	coef  *big.Int
+	scale int
+}
+
+func newNumericVar(d decimal128.Decimal) numericVar {
+	_, neg, sig, exp := d.Decompose(nil)
+
+	coef := new(big.Int).SetBytes(sig)
+	if exp > 0 {
+		coef.Mul(coef, pow10(int(exp)))
+		exp = 0
+	}
+
+	if neg {
+		coef.Neg(coef)
+	}
+
+	return numericVar{coef: coef, scale: int(-exp)}
+}
+
+// decimal converts v into a decimal128.Decimal. Trailing zeros are dropped
+// if v has more than 34 digits, and errNumericOverflow is returned if that
+// is not enough to make it fit.
+func (v numericVar) decimal() (decimal128.Decimal, error) {
+	neg := v.coef.Sign() < 0
+	abs := new(big.Int).Abs(v.coef)
+	scale := v.scale
+
+	if abs.Cmp(maxCoefficient) > 0 {
+		r := new(big.Int)
+		for abs.Cmp(maxCoefficient) > 0 {
+			q, _ := new(big.Int).QuoRem(abs, bigTen, r)
+			if r.Sign() != 0 {
+				return decimal128.Decimal{}, errNumericOverflow
+			}
+
+			abs = q
+			scale--
+		}
+	}
+
+	var d decimal128.Decimal
+	if err := d.Compose(0, neg, abs.Bytes(), int32(-scale)); err != nil {
+		return decimal128.Decimal{}, errNumericOverflow
+	}
+
+	return d, nil
+}
+
+// maxUnscaledScale bounds the scales of unscaledDecimal, far beyond the
+// exponent range of decimal128.
+const maxUnscaledScale = 1 << 16
+
+// unscaledDecimal returns coef * 10^-scale under the overflow rules of
+// numericVar.decimal. Unlike that of a numericVar, scale may be negative; it
+// then becomes a positive exponent without multiplying coef out.
+func unscaledDecimal(coef *big.Int, scale int) (decimal128.Decimal, error) {
+	if scale > maxUnscaledScale || scale < -maxUnscaledScale {
+		return decimal128.Decimal{}, errNumericOverflow
+	}
+
+	return numericVar{coef: coef, scale: scale}.decimal()
+}
+
+// weight returns the weight and the first digit of v in PostgreSQL's base
+// 10000 representation.
+func (v numericVar) weight() (int, int) {
+	w, digits := v.nbase()
+	if len(digits) == 0 {
+		return 0, 0
+	}
+
+	return w, digits[0]
+}
+
+// nbase returns the weight and the digits of |v| in PostgreSQL's base 10000
+// representation, without leading or trailing zero digits.
+func (v numericVar) nbase() (int, []int) {
+	if v.coef.Sign() == 0 {
+		return 0, nil
+	}
+
+	abs := new(big.Int).Abs(v.coef)
+	scale := v.scale
+	if pad := (decDigits - scale%decDigits) % decDigits; pad > 0 {
+		abs.Mul(abs, pow10(pad))
+		scale += pad
+	}
+
+	s := abs.String()
+	if len(s) < scale {
+		s = strings.Repeat("0", scale-len(s)) + s
+	}
+	if n := (len(s) - scale) % decDigits; n > 0 {
+		s = strings.Repeat("0", decDigits-n) + s
+	}
+
+	w := (len(s)-scale)/decDigits - 1
+	digits := make([]int, 0, len(s)/decDigits)
+	for i := 0; i < len(s); i += decDigits {
+		d, _ := strconv.Atoi(s[i : i+decDigits])
+		digits = append(digits, d)
+	}
+
+	for digits[0] == 0 {
+		digits = digits[1:]
+		w--
+	}
+	for digits[len(digits)-1] == 0 {
+		digits = digits[:len(digits)-1]
+	}
+
+	return w, digits
+}
+
+// selectDivScale is select_div_scale from numeric.c.
+func selectDivScale(a, b numericVar) int {
+	weight1, firstDigit1 := a.weight()
+	weight2, firstDigit2 := b.weight()
+
+	// If the two first digits are equal, assume that a is less than b.
+	qweight := weight1 - weight2
+	if firstDigit1 <= firstDigit2 {
+		qweight--
+	}
+
+	rscale := numericMinSigDigits - qweight*decDigits
+	rscale = max(rscale, a.scale, b.scale, numericMinDisplayScale)
+	return min(rscale, numericMaxDisplayScale)
+}
+
+// divVar returns a / b with rscale digits after the decimal point, rounded
+// half away from zero if round is set and truncated otherwise.
+func divVar(a, b numericVar, rscale int, round bool) numericVar {
+	n := new(big.Int).Set(a.coef)
+	d := new(big.Int).Set(b.coef)
+
+	if k := b.scale - a.scale + rscale; k >= 0 {
+		n.Mul(n, pow10(k))
+	} else {
+		d.Mul(d, pow10(-k))
+	}
+
+	q, r := new(big.Int).QuoRem(n, d, new(big.Int))
+	if round {
+		r.Abs(r).Lsh(r, 1)
+		if r.CmpAbs(d) >= 0 {
+			if n.Sign() == d.Sign() {
+				q.Add(q, big.NewInt(1))
+			} else {
+				q.Sub(q, big.NewInt(1))
+			}
+		}
+	}
+
+	return numericVar{coef: q, scale: rscale}
+}
+
+func mulVar(a, b numericVar) numericVar {
+	return numericVar{coef: new(big.Int).Mul(a.coef, b.coef), scale: a.scale + b.scale}
+}
+
+func addVar(a, b numericVar) numericVar {
+	scale := max(a.scale, b.scale)
+	x := new(big.Int).Mul(a.coef, pow10(scale-a.scale))
+	y := new(big.Int).Mul(b.coef, pow10(scale-b.scale))
+	return numericVar{coef: x.Add(x, y), scale: scale}
+}
+
+func subVar(a, b numericVar) numericVar {
+	scale := max(a.scale, b.scale)
+	x := new(big.Int).Mul(a.coef, pow10(scale-a.scale))
+	y := new(big.Int).Mul(b.coef, pow10(scale-b.scale))
+	return numericVar{coef: x.Sub(x, y), scale: scale}
+}
+
+func pow10(n int) *big.Int {
+	return new(big.Int).Exp(bigTen, big.NewInt(int64(n)), nil)
+}
+
+// signOf returns the sign of a non-NaN value.
+func signOf(d decimal128.Decimal) int {
+	if d.IsZero() {
+		return 0
+	}
+	if d.Signbit() {
+		return -1
+	}
+	return 1
+}
+
+// infWithSign returns an infinity with the given sign, or NaN for a zero sign.
+func infWithSign(sign int) decimal128.Decimal {
+	if sign == 0 {
+		return decimal128.NaN()
+	}
+	return decimal128.Inf(sign)
+}
+
+// divSpecial handles the NaN and infinity cases shared by numeric_div and
+// numeric_div_trunc.
+func divSpecial(a, b decimal128.Decimal) (decimal128.Decimal, error) {
+	if a.IsNaN() || b.IsNaN() || (a.IsInf(0) && b.IsInf(0)) {
+		return decimal128.NaN(), nil
+	}
+
+	if a.IsInf(0) {
+		if b.IsZero() {
+			return decimal128.Decimal{}, errDivisionByZero
+		}
+		return decimal128.Inf(signOf(a) * signOf(b)), nil
+	}
+
+	// a is finite and b is infinite.
+	return decimal128.Decimal{}, nil
+}
+
+// NumericDiv returns a / b with the result scale and rounding PostgreSQL uses
+// for the numeric / operator.
+func NumericDiv(a, b decimal128.Decimal) (decimal128.Decimal, error) {
+	if a.IsNaN() || b.IsNaN() || a.IsInf(0) || b.IsInf(0) {
+		return divSpecial(a, b)
+	}
+
+	if b.IsZero() {
+		return decimal128.Decimal{}, errDivisionByZero
+	}
+
+	x, y := newNumericVar(a), newNumericVar(b)
+	return divVar(x, y, selectDivScale(x, y), true).decimal()
+}
+
+// NumericDivTrunc returns the truncated integer quotient of a / b, like
+// PostgreSQL's div(numeric, numeric).
+func NumericDivTrunc(a, b decimal128.Decimal) (decimal128.Decimal, error) {
+	if a.IsNaN() || b.IsNaN() || a.IsInf(0) || b.IsInf(0) {
+		return divSpecial(a, b)
+	}
+
+	if b.IsZero() {
+		return decimal128.Decimal{}, errDivisionByZero
+	}
+
+	return divVar(newNumericVar(a), newNumericVar(b), 0, false).decimal()
+}
+
+// NumericMul returns a * b with the exact product and result scale PostgreSQL
+// uses for the numeric * operator.
+func NumericMul(a, b decimal128.Decimal) (decimal128.Decimal, error) {
+	if a.IsNaN() || b.IsNaN() {
+		return decimal128.NaN(), nil
+	}
+
+	if a.IsInf(0) {
+		return infWithSign(signOf(a) * signOf(b)), nil
+	}
+
+	if b.IsInf(0) {
+		return infWithSign(signOf(a) * signOf(b)), nil
+	}
+
+	return mulVar(newNumericVar(a), newNumericVar(b)).decimal()
+}
+
+// NumericMod returns a % b computed as a - trunc(a / b) * b, with the result
+// scale PostgreSQL uses for the numeric % operator.
+func NumericMod(a, b decimal128.Decimal) (decimal128.Decimal, error) {
+	if a.IsNaN() || b.IsNaN() {
+		return decimal128.NaN(), nil
+	}
+
+	if a.IsInf(0) {
+		if b.IsZero() {
+			return decimal128.Decimal{}, errDivisionByZero
+		}
+		return decimal128.NaN(), nil
+	}
+
+	if b.IsInf(0) {
+		return a, nil
+	}
+
+	if b.IsZero() {
+		return decimal128.Decimal{}, errDivisionByZero
+	}
+
+	x, y := newNumericVar(a), newNumericVar(b)
+	q := divVar(x, y, 0, false)
+	return subVar(x, mulVar(y, q)).decimal()
+}
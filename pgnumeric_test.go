@@ -0,0 +1,116 @@
+package decimal_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/ingothierack/decimal128"
+	pgxdecimal "github.com/ingothierack/pgx-woodsbury-decimal128"
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/require"
+)
+
+type numericOpTest struct {
+	op       string
+	a, b     string
+	expected string
+}
+
+// numericOpTests holds the text output of PostgreSQL for SELECT a <op> b with
+// both operands cast to numeric. TestNumericOpsMatchServer checks them against
+// a live server.
+var numericOpTests = []numericOpTest{
+	{"/", "1", "3", "0.33333333333333333333"},
+	{"/", "2", "3", "0.66666666666666666667"},
+	{"/", "-2", "3", "-0.66666666666666666667"},
+	{"/", "10", "4", "2.5000000000000000"},
+	{"/", "100", "7", "14.2857142857142857"},
+	{"/", "12345678", "1", "12345678.000000000000"},
+	{"/", "1", "100000", "0.000010000000000000000000"},
+	{"/", "0.1", "3", "0.03333333333333333333"},
+	{"/", "1.000000000000000000000000", "3", "0.333333333333333333333333"},
+	{"/", "9999", "9999", "1.00000000000000000000"},
+	{"/", "123456789012345678901234", "0.5", "246913578024691357802468.0"},
+	{"/", "7", "-0.0025", "-2800.0000000000000000"},
+	{"/", "1", "Infinity", "0"},
+	{"/", "-Infinity", "2", "-Infinity"},
+	{"/", "Infinity", "Infinity", "NaN"},
+	{"/", "NaN", "0", "NaN"},
+
+	{"*", "1.5", "2.25", "3.375"},
+	{"*", "2.50", "4", "10.00"},
+	{"*", "-0.001", "0.001", "-0.000001"},
+	{"*", "123456789012345678", "1000000000000.01", "123456789012346912567890123456.78"},
+	{"*", "Infinity", "-2", "-Infinity"},
+	{"*", "Infinity", "0", "NaN"},
+
+	{"%", "10.5", "3", "1.5"},
+	{"%", "-7", "3", "-1"},
+	{"%", "7", "-3", "1"},
+	{"%", "7.00", "2.5", "2.00"},
+	{"%", "5", "Infinity", "5"},
+	{"%", "Infinity", "3", "NaN"},
+
+	{"div", "10.5", "3", "3"},
+	{"div", "-7", "2", "-3"},
+	{"div", "0.9", "0.2", "4"},
+	{"div", "1", "-Infinity", "0"},
+}
+
+func numericOp(op string, a, b decimal128.Decimal) (decimal128.Decimal, error) {
+	switch op {
+	case "/":
+		return pgxdecimal.NumericDiv(a, b)
+	case "*":
+		return pgxdecimal.NumericMul(a, b)
+	case "%":
+		return pgxdecimal.NumericMod(a, b)
+	case "div":
+		return pgxdecimal.NumericDivTrunc(a, b)
+	}
+
+	panic("unknown op " + op)
+}
+
+func TestNumericOps(t *testing.T) {
+	for _, tt := range numericOpTests {
+		result, err := numericOp(tt.op, decimal128.MustParse(tt.a), decimal128.MustParse(tt.b))
+		require.NoError(t, err, "%s %s %s", tt.a, tt.op, tt.b)
+		require.Equal(t, tt.expected, pgxdecimal.Decimal(result).String(), "%s %s %s", tt.a, tt.op, tt.b)
+	}
+}
+
+func TestNumericOpsErrors(t *testing.T) {
+	one := decimal128.FromInt64(1)
+	zero := decimal128.FromInt64(0)
+
+	for _, op := range []string{"/", "%", "div"} {
+		_, err := numericOp(op, one, zero)
+		require.EqualError(t, err, pgxdecimal.ErrDivisionByZero, op)
+
+		_, err = numericOp(op, decimal128.Inf(1), zero)
+		require.EqualError(t, err, pgxdecimal.ErrDivisionByZero, op)
+	}
+
+	_, err := pgxdecimal.NumericDiv(decimal128.MustParse("99999999999999999999.99999999999999"), decimal128.MustParse("0.7"))
+	require.EqualError(t, err, pgxdecimal.ErrNumericOverflow)
+}
+
+func TestNumericOpsMatchServer(t *testing.T) {
+	defaultConnTestRunner.RunTest(context.Background(), t, func(ctx context.Context, t testing.TB, conn *pgx.Conn) {
+		for _, tt := range numericOpTests {
+			var sql string
+			if tt.op == "div" {
+				sql = "select div($1::numeric, $2::numeric)::text"
+			} else {
+				sql = fmt.Sprintf("select ($1::numeric %s $2::numeric)::text", tt.op)
+			}
+
+			var result string
+			err := conn.QueryRow(ctx, sql, tt.a, tt.b).Scan(&result)
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, result, "%s %s %s", tt.a, tt.op, tt.b)
+		}
+	})
+}
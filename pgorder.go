@@ -0,0 +1,94 @@
+package decimal
+
+import (
+	"slices"
+
+	"github.com/ingothierack/decimal128"
+)
+
+// CompareNumeric returns -1, 0 or +1 depending on whether a is less than, equal
+// to or greater than b in PostgreSQL numeric ordering, where NaN is equal to
+// itself and greater than any other value, including Infinity. Unlike
+// decimal128.Compare, it matches the order of ORDER BY on a numeric column.
+func CompareNumeric(a, b decimal128.Decimal) int {
+	return cmpNumeric(a, b)
+}
+
+// NullsOrder is the placement of NULL values in a SortOrder.
+type NullsOrder int
+
+const (
+	// NullsDefault places NULL values as PostgreSQL does when the ORDER BY
+	// clause does not say: last in ascending and first in descending order,
+	// as if NULL were larger than any value.
+	NullsDefault NullsOrder = iota
+
+	// NullsFirst is NULLS FIRST.
+	NullsFirst
+
+	// NullsLast is NULLS LAST.
+	NullsLast
+)
+
+// SortOrder is the ordering of a numeric column in an ORDER BY clause. The zero
+// value is ASC NULLS LAST, PostgreSQL's default.
+type SortOrder struct {
+	Desc  bool
+	Nulls NullsOrder
+}
+
+// Compare compares a and b in the order o, returning a negative number if a
+// sorts before b, a positive number if it sorts after b, and 0 if they are
+// equal.
+func (o SortOrder) Compare(a, b decimal128.Decimal) int {
+	if o.Desc {
+		return cmpNumeric(b, a)
+	}
+	return cmpNumeric(a, b)
+}
+
+// CompareDecimal is like Compare for Decimal values.
+func (o SortOrder) CompareDecimal(a, b Decimal) int {
+	return o.Compare(decimal128.Decimal(a), decimal128.Decimal(b))
+}
+
+// CompareNullDecimal is like Compare for NullDecimal values, placing NULL
+// values as o.Nulls says. NULL values are equal to each other.
+func (o SortOrder) CompareNullDecimal(a, b NullDecimal) int {
+	switch {
+	case !a.Valid && !b.Valid:
+		return 0
+	case !a.Valid:
+		return o.nullCmp()
+	case !b.Valid:
+		return -o.nullCmp()
+	}
+
+	return o.Compare(a.Decimal, b.Decimal)
+}
+
+// nullCmp returns the result of comparing NULL with a non-NULL value.
+func (o SortOrder) nullCmp() int {
+	nullsFirst := o.Nulls == NullsFirst || o.Nulls == NullsDefault && o.Desc
+	if nullsFirst {
+		return -1
+	}
+	return 1
+}
+
+// Sort sorts s in the order o. The sort is stable, so values that compare
+// equal, such as 1.0 and 1, keep their relative order.
+func Sort(s []decimal128.Decimal, o SortOrder) {
+	slices.SortStableFunc(s, o.Compare)
+}
+
+// SortDecimal sorts s in the order o, like Sort.
+func SortDecimal(s []Decimal, o SortOrder) {
+	slices.SortStableFunc(s, o.CompareDecimal)
+}
+
+// SortNullDecimal sorts s in the order o, like Sort, placing NULL values as
+// o.Nulls says.
+func SortNullDecimal(s []NullDecimal, o SortOrder) {
+	slices.SortStableFunc(s, o.CompareNullDecimal)
+}
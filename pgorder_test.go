@@ -0,0 +1,147 @@
+package decimal_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/ingothierack/decimal128"
+	pgxdecimal "github.com/ingothierack/pgx-woodsbury-decimal128"
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/require"
+)
+
+// orderTestValues are distinct values in PostgreSQL numeric order, with "" for
+// NULL.
+var orderTestValues = []string{"", "-Infinity", "-1e20", "-1.5", "-0.001", "0", "0.001", "1", "12345.678", "1e20", "Infinity", "NaN"}
+
+var sortOrderTests = []struct {
+	order    pgxdecimal.SortOrder
+	sql      string
+	expected []string
+}{
+	{
+		pgxdecimal.SortOrder{},
+		"asc",
+		[]string{"-Infinity", "-1e20", "-1.5", "-0.001", "0", "0.001", "1", "12345.678", "1e20", "Infinity", "NaN", ""},
+	},
+	{
+		pgxdecimal.SortOrder{Nulls: pgxdecimal.NullsFirst},
+		"asc nulls first",
+		[]string{"", "-Infinity", "-1e20", "-1.5", "-0.001", "0", "0.001", "1", "12345.678", "1e20", "Infinity", "NaN"},
+	},
+	{
+		pgxdecimal.SortOrder{Desc: true},
+		"desc",
+		[]string{"", "NaN", "Infinity", "1e20", "12345.678", "1", "0.001", "0", "-0.001", "-1.5", "-1e20", "-Infinity"},
+	},
+	{
+		pgxdecimal.SortOrder{Desc: true, Nulls: pgxdecimal.NullsLast},
+		"desc nulls last",
+		[]string{"NaN", "Infinity", "1e20", "12345.678", "1", "0.001", "0", "-0.001", "-1.5", "-1e20", "-Infinity", ""},
+	},
+}
+
+func nullDecimalStrings(values []pgxdecimal.NullDecimal) []string {
+	s := make([]string, len(values))
+	for i, v := range values {
+		if v.Valid {
+			s[i] = v.Decimal.String()
+		}
+	}
+	return s
+}
+
+func shuffledNullDecimals() []pgxdecimal.NullDecimal {
+	var values []pgxdecimal.NullDecimal
+	for i := range orderTestValues {
+		s := orderTestValues[(i*5)%len(orderTestValues)]
+		if s == "" {
+			values = append(values, pgxdecimal.NullDecimal{})
+		} else {
+			values = append(values, pgxdecimal.NullDecimal{Decimal: decimal128.MustParse(s), Valid: true})
+		}
+	}
+	return values
+}
+
+func TestCompareNumeric(t *testing.T) {
+	require.Equal(t, 0, pgxdecimal.CompareNumeric(decimal128.NaN(), decimal128.NaN()))
+	require.Equal(t, 1, pgxdecimal.CompareNumeric(decimal128.NaN(), decimal128.Inf(1)))
+	require.Equal(t, -1, pgxdecimal.CompareNumeric(decimal128.Inf(1), decimal128.NaN()))
+	require.Equal(t, 0, pgxdecimal.CompareNumeric(decimal128.MustParse("1.00"), decimal128.FromInt64(1)))
+	require.Equal(t, 0, pgxdecimal.CompareNumeric(decimal128.MustParse("-0"), decimal128.FromInt64(0)))
+}
+
+func TestSortNullDecimal(t *testing.T) {
+	for _, tt := range sortOrderTests {
+		values := shuffledNullDecimals()
+		pgxdecimal.SortNullDecimal(values, tt.order)
+
+		expected := make([]string, len(tt.expected))
+		for i, s := range tt.expected {
+			if s != "" {
+				expected[i] = decimal128.MustParse(s).String()
+			}
+		}
+		require.Equal(t, expected, nullDecimalStrings(values), tt.sql)
+	}
+}
+
+func TestSort(t *testing.T) {
+	values := []decimal128.Decimal{
+		decimal128.NaN(), decimal128.FromInt64(2), decimal128.Inf(-1), decimal128.MustParse("1.0"),
+		decimal128.Inf(1), decimal128.FromInt64(1), decimal128.NaN(),
+	}
+
+	pgxdecimal.Sort(values, pgxdecimal.SortOrder{})
+	require.Equal(t, []string{"-Infinity", "1.0", "1", "2", "Infinity", "NaN", "NaN"}, decimalStrings(values))
+
+	pgxdecimal.Sort(values, pgxdecimal.SortOrder{Desc: true})
+	require.Equal(t, []string{"NaN", "NaN", "Infinity", "2", "1.0", "1", "-Infinity"}, decimalStrings(values))
+
+	decimals := []pgxdecimal.Decimal{pgxdecimal.Decimal(decimal128.NaN()), pgxdecimal.Decimal(decimal128.FromInt64(-3))}
+	pgxdecimal.SortDecimal(decimals, pgxdecimal.SortOrder{})
+	require.Equal(t, "-3", decimals[0].String())
+	require.Equal(t, "NaN", decimals[1].String())
+}
+
+func decimalStrings(values []decimal128.Decimal) []string {
+	s := make([]string, len(values))
+	for i, v := range values {
+		s[i] = pgxdecimal.Decimal(v).String()
+	}
+	return s
+}
+
+func TestSortNullDecimalMatchServer(t *testing.T) {
+	defaultConnTestRunner.RunTest(context.Background(), t, func(ctx context.Context, t testing.TB, conn *pgx.Conn) {
+		input := make([]*string, len(orderTestValues))
+		for i, s := range orderTestValues {
+			if s != "" {
+				input[i] = &s
+			}
+		}
+
+		for _, tt := range sortOrderTests {
+			sql := fmt.Sprintf("select coalesce(x::text, 'NULL') from unnest($1::text[]::numeric[]) as t(x) order by x %s", tt.sql)
+			rows, err := conn.Query(ctx, sql, input)
+			require.NoError(t, err)
+
+			result, err := pgx.CollectRows(rows, pgx.RowTo[string])
+			require.NoError(t, err)
+
+			values := shuffledNullDecimals()
+			pgxdecimal.SortNullDecimal(values, tt.order)
+
+			expected := make([]string, len(values))
+			for i, v := range values {
+				expected[i] = "NULL"
+				if v.Valid {
+					expected[i] = pgxdecimal.NumericOut(v.Decimal)
+				}
+			}
+			require.Equal(t, expected, result, tt.sql)
+		}
+	})
+}
@@ -0,0 +1,55 @@
+package decimal
+
+import (
+	"math/big"
+
+	"github.com/ingothierack/decimal128"
+)
+
+// NumericOut returns d as PostgreSQL's numeric_out prints it: plain notation
+// that keeps the scale of d, with NaN, Infinity and -Infinity spelled out.
+// Zero has no scale in decimal128 and is printed as "0"; use NumericOutScale
+// to print it with the scale of a column.
+func NumericOut(d decimal128.Decimal) string {
+	return string(AppendNumericOut(nil, d))
+}
+
+// AppendNumericOut appends d to buf as NumericOut formats it.
+func AppendNumericOut(buf []byte, d decimal128.Decimal) []byte {
+	return appendDecimalText(buf, d)
+}
+
+// NumericOutScale returns d as numeric_out prints it after it has been stored
+// with dscale digits after the decimal point, rounding half away from zero as
+// PostgreSQL does for a numeric(p, dscale) column. A negative dscale rounds to
+// the left of the decimal point and prints no fractional digits. NaN and
+// infinite values are printed as by NumericOut.
+func NumericOutScale(d decimal128.Decimal, dscale int) string {
+	if d.IsNaN() || d.IsInf(0) {
+		return NumericOut(d)
+	}
+
+	return string(roundVar(newNumericVar(d), clampResultScale(dscale), true).appendText(nil))
+}
+
+// appendText appends v in plain notation with exactly v.scale digits after
+// the decimal point.
+func (v numericVar) appendText(buf []byte) []byte {
+	if v.coef.Sign() < 0 {
+		buf = append(buf, '-')
+	}
+
+	digits := new(big.Int).Abs(v.coef).Append(nil, 10)
+	for len(digits) <= v.scale {
+		digits = append([]byte{'0'}, digits...)
+	}
+
+	intLen := len(digits) - v.scale
+	buf = append(buf, digits[:intLen]...)
+	if v.scale > 0 {
+		buf = append(buf, '.')
+		buf = append(buf, digits[intLen:]...)
+	}
+
+	return buf
+}
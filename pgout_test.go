@@ -0,0 +1,76 @@
+package decimal_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ingothierack/decimal128"
+	pgxdecimal "github.com/ingothierack/pgx-woodsbury-decimal128"
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/require"
+)
+
+// numericOutTests holds the text output of PostgreSQL for SELECT x::numeric.
+// TestNumericOutMatchServer checks them against a live server.
+var numericOutTests = []struct {
+	value    string
+	expected string
+}{
+	{"1", "1"},
+	{"1.00", "1.00"},
+	{"-0.000123", "-0.000123"},
+	{"9345678901234567890.123456789012345", "9345678901234567890.123456789012345"},
+	{"-9345678901234567890.123456789012345", "-9345678901234567890.123456789012345"},
+	{"1.5e+20", "150000000000000000000"},
+	{"1.5e-20", "0.000000000000000000015"},
+	{"-0", "0"},
+	{"NaN", "NaN"},
+	{"Infinity", "Infinity"},
+	{"-Infinity", "-Infinity"},
+}
+
+// numericOutScaleTests holds the text output of PostgreSQL for SELECT
+// round(x::numeric, dscale).
+var numericOutScaleTests = []struct {
+	value    string
+	dscale   int
+	expected string
+}{
+	{"0", 2, "0.00"},
+	{"-0.004", 2, "0.00"},
+	{"-0.005", 2, "-0.01"},
+	{"12.5", 0, "13"},
+	{"1.5", 4, "1.5000"},
+	{"1250", -2, "1300"},
+	{"NaN", 2, "NaN"},
+}
+
+func TestNumericOut(t *testing.T) {
+	for _, tt := range numericOutTests {
+		d := decimal128.MustParse(tt.value)
+		require.Equal(t, tt.expected, pgxdecimal.NumericOut(d), tt.value)
+		require.Equal(t, "x="+tt.expected, string(pgxdecimal.AppendNumericOut([]byte("x="), d)), tt.value)
+	}
+
+	for _, tt := range numericOutScaleTests {
+		require.Equal(t, tt.expected, pgxdecimal.NumericOutScale(decimal128.MustParse(tt.value), tt.dscale), "%s %d", tt.value, tt.dscale)
+	}
+}
+
+func TestNumericOutMatchServer(t *testing.T) {
+	defaultConnTestRunner.RunTest(context.Background(), t, func(ctx context.Context, t testing.TB, conn *pgx.Conn) {
+		for _, tt := range numericOutTests {
+			var result string
+			err := conn.QueryRow(ctx, "select $1::numeric::text", tt.value).Scan(&result)
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, result, tt.value)
+		}
+
+		for _, tt := range numericOutScaleTests {
+			var result string
+			err := conn.QueryRow(ctx, "select round($1::numeric, $2::int)::text", tt.value, tt.dscale).Scan(&result)
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, result, "%s %d", tt.value, tt.dscale)
+		}
+	})
+}
@@ -0,0 +1,134 @@
+package decimal
+
+import (
+	"fmt"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// Kinds of column data in pgoutput TupleData, the values of
+// pglogrepl.TupleDataColumn.DataType.
+const (
+	TupleDataNull      = 'n'
+	TupleDataUnchanged = 'u'
+	TupleDataText      = 't'
+	TupleDataBinary    = 'b'
+)
+
+// RelationColumn is a column of a pgoutput Relation message.
+type RelationColumn struct {
+	Name string
+
+	// DataType is the OID of the column's type.
+	DataType uint32
+
+	TypeModifier int32
+}
+
+// Relation is the metadata of a pgoutput Relation message, which describes
+// the columns of the tuples of later Insert, Update and Delete messages. With
+// pglogrepl, it is built from the fields of a RelationMessage.
+type Relation struct {
+	Namespace string
+	Name      string
+	Columns   []RelationColumn
+}
+
+// TupleColumn is a column of pgoutput TupleData. With pglogrepl, it is built
+// from a TupleDataColumn.
+type TupleColumn struct {
+	// DataType is one of TupleDataNull, TupleDataUnchanged, TupleDataText
+	// and TupleDataBinary.
+	DataType byte
+	Data     []byte
+}
+
+// TupleDecimal is the value of a column of a pgoutput tuple.
+type TupleDecimal struct {
+	NullDecimal
+
+	// Unchanged is set for a TOASTed value that was not changed by an
+	// update, which pgoutput does not send. NullDecimal is then NULL, but
+	// the column keeps its old value rather than becoming NULL.
+	Unchanged bool
+}
+
+// DecodeTupleDecimal decodes the tuple column data of type dataType, as m
+// scans it into a NullDecimal. The column may be of any type that m can scan
+// into a NullDecimal, such as numeric, int8 or float8, and the NaN and
+// infinity rules of query results apply.
+func DecodeTupleDecimal(m *pgtype.Map, dataType uint32, col TupleColumn) (TupleDecimal, error) {
+	var format int16
+	switch col.DataType {
+	case TupleDataNull:
+		return TupleDecimal{}, nil
+	case TupleDataUnchanged:
+		return TupleDecimal{Unchanged: true}, nil
+	case TupleDataText:
+		format = pgtype.TextFormatCode
+	case TupleDataBinary:
+		format = pgtype.BinaryFormatCode
+	default:
+		return TupleDecimal{}, fmt.Errorf("unknown tuple data type %q", col.DataType)
+	}
+
+	var d NullDecimal
+	if err := m.Scan(dataType, format, col.Data, &d); err != nil {
+		return TupleDecimal{}, err
+	}
+
+	return TupleDecimal{NullDecimal: d}, nil
+}
+
+// ColumnIndex returns the index of the column name, or -1.
+func (r *Relation) ColumnIndex(name string) int {
+	for i, col := range r.Columns {
+		if col.Name == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// DecodeDecimal decodes the column name of tuple, a tuple of r, with
+// DecodeTupleDecimal.
+func (r *Relation) DecodeDecimal(m *pgtype.Map, tuple []TupleColumn, name string) (TupleDecimal, error) {
+	if len(tuple) != len(r.Columns) {
+		return TupleDecimal{}, fmt.Errorf("relation %s.%s has %d columns, tuple has %d", r.Namespace, r.Name, len(r.Columns), len(tuple))
+	}
+
+	i := r.ColumnIndex(name)
+	if i < 0 {
+		return TupleDecimal{}, fmt.Errorf("relation %s.%s has no column %q", r.Namespace, r.Name, name)
+	}
+
+	d, err := DecodeTupleDecimal(m, r.Columns[i].DataType, tuple[i])
+	if err != nil {
+		return TupleDecimal{}, fmt.Errorf("column %s: %w", name, err)
+	}
+
+	return d, nil
+}
+
+// DecodeDecimals decodes all numeric columns of tuple, a tuple of r, with
+// DecodeTupleDecimal, and returns them by column name.
+func (r *Relation) DecodeDecimals(m *pgtype.Map, tuple []TupleColumn) (map[string]TupleDecimal, error) {
+	if len(tuple) != len(r.Columns) {
+		return nil, fmt.Errorf("relation %s.%s has %d columns, tuple has %d", r.Namespace, r.Name, len(r.Columns), len(tuple))
+	}
+
+	values := make(map[string]TupleDecimal)
+	for i, col := range r.Columns {
+		if col.DataType != pgtype.NumericOID {
+			continue
+		}
+
+		d, err := DecodeTupleDecimal(m, col.DataType, tuple[i])
+		if err != nil {
+			return nil, fmt.Errorf("column %s: %w", col.Name, err)
+		}
+		values[col.Name] = d
+	}
+
+	return values, nil
+}
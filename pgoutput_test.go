@@ -0,0 +1,87 @@
+package decimal_test
+
+import (
+	"testing"
+
+	"github.com/ingothierack/decimal128"
+	pgxdecimal "github.com/ingothierack/pgx-woodsbury-decimal128"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+)
+
+var pgoutputTestRelation = pgxdecimal.Relation{
+	Namespace: "public",
+	Name:      "accounts",
+	Columns: []pgxdecimal.RelationColumn{
+		{Name: "id", DataType: pgtype.Int8OID, TypeModifier: -1},
+		{Name: "balance", DataType: pgtype.NumericOID, TypeModifier: -1},
+		{Name: "limit", DataType: pgtype.NumericOID, TypeModifier: -1},
+		{Name: "history", DataType: pgtype.NumericOID, TypeModifier: -1},
+	},
+}
+
+func TestDecodeTupleDecimal(t *testing.T) {
+	m := newCopyTestMap()
+
+	d, err := pgxdecimal.DecodeTupleDecimal(m, pgtype.NumericOID, pgxdecimal.TupleColumn{DataType: pgxdecimal.TupleDataText, Data: []byte("12.50")})
+	require.NoError(t, err)
+	require.True(t, d.Valid)
+	require.False(t, d.Unchanged)
+	require.Equal(t, "12.50", pgxdecimal.NumericOut(d.Decimal))
+
+	data := pgxdecimal.AppendNumericSend(nil, decimal128.MustParse("-0.000001"))
+	d, err = pgxdecimal.DecodeTupleDecimal(m, pgtype.NumericOID, pgxdecimal.TupleColumn{DataType: pgxdecimal.TupleDataBinary, Data: data})
+	require.NoError(t, err)
+	require.Equal(t, "-0.000001", pgxdecimal.NumericOut(d.Decimal))
+
+	d, err = pgxdecimal.DecodeTupleDecimal(m, pgtype.Int8OID, pgxdecimal.TupleColumn{DataType: pgxdecimal.TupleDataText, Data: []byte("42")})
+	require.NoError(t, err)
+	require.Equal(t, "42", d.Decimal.String())
+
+	d, err = pgxdecimal.DecodeTupleDecimal(m, pgtype.NumericOID, pgxdecimal.TupleColumn{DataType: pgxdecimal.TupleDataNull})
+	require.NoError(t, err)
+	require.Equal(t, pgxdecimal.TupleDecimal{}, d)
+
+	d, err = pgxdecimal.DecodeTupleDecimal(m, pgtype.NumericOID, pgxdecimal.TupleColumn{DataType: pgxdecimal.TupleDataUnchanged})
+	require.NoError(t, err)
+	require.False(t, d.Valid)
+	require.True(t, d.Unchanged)
+
+	_, err = pgxdecimal.DecodeTupleDecimal(m, pgtype.NumericOID, pgxdecimal.TupleColumn{DataType: pgxdecimal.TupleDataText, Data: []byte("NaN")})
+	require.Error(t, err)
+
+	_, err = pgxdecimal.DecodeTupleDecimal(m, pgtype.NumericOID, pgxdecimal.TupleColumn{DataType: 'x', Data: []byte("1")})
+	require.Error(t, err)
+}
+
+func TestRelationDecodeDecimals(t *testing.T) {
+	m := newCopyTestMap()
+	tuple := []pgxdecimal.TupleColumn{
+		{DataType: pgxdecimal.TupleDataText, Data: []byte("1")},
+		{DataType: pgxdecimal.TupleDataText, Data: []byte("100.25")},
+		{DataType: pgxdecimal.TupleDataNull},
+		{DataType: pgxdecimal.TupleDataUnchanged},
+	}
+
+	d, err := pgoutputTestRelation.DecodeDecimal(m, tuple, "balance")
+	require.NoError(t, err)
+	require.Equal(t, "100.25", d.Decimal.String())
+
+	_, err = pgoutputTestRelation.DecodeDecimal(m, tuple, "missing")
+	require.Error(t, err)
+
+	_, err = pgoutputTestRelation.DecodeDecimal(m, tuple[:3], "balance")
+	require.Error(t, err)
+
+	values, err := pgoutputTestRelation.DecodeDecimals(m, tuple)
+	require.NoError(t, err)
+	require.Len(t, values, 3)
+	require.True(t, values["balance"].Valid)
+	require.False(t, values["limit"].Valid)
+	require.False(t, values["limit"].Unchanged)
+	require.True(t, values["history"].Unchanged)
+
+	tuple[1].Data = []byte("Infinity")
+	_, err = pgoutputTestRelation.DecodeDecimals(m, tuple)
+	require.ErrorContains(t, err, "column balance")
+}
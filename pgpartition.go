@@ -0,0 +1,336 @@
+package decimal
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/ingothierack/decimal128"
+)
+
+// RangeBoundKind is the kind of a range partition bound datum, like
+// PartitionRangeDatumKind in PostgreSQL.
+type RangeBoundKind int
+
+// Kinds of range bound datums, in their sort order.
+const (
+	RangeBoundMinValue RangeBoundKind = iota - 1
+	RangeBoundValue
+	RangeBoundMaxValue
+)
+
+// RangeBound is one column of a range partition bound: MINVALUE, MAXVALUE or
+// a value.
+type RangeBound struct {
+	Kind  RangeBoundKind
+	Value decimal128.Decimal
+}
+
+// RangePartition is a partition of a table partitioned by range on numeric
+// columns, created with FOR VALUES FROM (From...) TO (To...) or as the
+// DEFAULT partition.
+type RangePartition struct {
+	Name    string
+	Default bool
+	From    []RangeBound
+	To      []RangeBound
+}
+
+// ParseRangePartitionBound parses the bound of the partition name as printed
+// by pg_get_expr(relpartbound, oid), such as
+// "FOR VALUES FROM ('0', MINVALUE) TO (10.5, MAXVALUE)" or "DEFAULT".
+func ParseRangePartitionBound(name, bound string) (RangePartition, error) {
+	p := RangePartition{Name: name}
+
+	l := &boundLexer{s: bound}
+	if l.keyword("DEFAULT") {
+		p.Default = true
+	} else {
+		if !l.keyword("FOR") || !l.keyword("VALUES") {
+			return RangePartition{}, fmt.Errorf("invalid partition bound %q", bound)
+		}
+		if !l.keyword("FROM") {
+			return RangePartition{}, fmt.Errorf("partition bound %q is not a range bound", bound)
+		}
+
+		var err error
+		if p.From, err = l.boundList(); err != nil {
+			return RangePartition{}, fmt.Errorf("invalid partition bound %q: %w", bound, err)
+		}
+		if !l.keyword("TO") {
+			return RangePartition{}, fmt.Errorf("invalid partition bound %q", bound)
+		}
+		if p.To, err = l.boundList(); err != nil {
+			return RangePartition{}, fmt.Errorf("invalid partition bound %q: %w", bound, err)
+		}
+	}
+
+	if l.skipSpace(); l.s != "" {
+		return RangePartition{}, fmt.Errorf("invalid partition bound %q", bound)
+	}
+
+	return p, nil
+}
+
+// boundLexer reads the tokens of a partition bound expression.
+type boundLexer struct {
+	s string
+}
+
+func (l *boundLexer) skipSpace() {
+	l.s = strings.TrimLeft(l.s, " \t\n\r")
+}
+
+// keyword consumes the keyword kw, matched case-insensitively, if it comes
+// next.
+func (l *boundLexer) keyword(kw string) bool {
+	l.skipSpace()
+	if len(l.s) < len(kw) || !strings.EqualFold(l.s[:len(kw)], kw) {
+		return false
+	}
+	if rest := l.s[len(kw):]; rest != "" && isIdentChar(rest[0]) {
+		return false
+	}
+
+	l.s = l.s[len(kw):]
+	return true
+}
+
+func (l *boundLexer) punct(c byte) bool {
+	l.skipSpace()
+	if l.s == "" || l.s[0] != c {
+		return false
+	}
+
+	l.s = l.s[1:]
+	return true
+}
+
+// boundList reads a parenthesized list of MINVALUE, MAXVALUE and numeric
+// constants, which pg_get_expr prints quoted unless they contain a decimal
+// point or an exponent.
+func (l *boundLexer) boundList() ([]RangeBound, error) {
+	if !l.punct('(') {
+		return nil, errors.New("expected (")
+	}
+
+	var bounds []RangeBound
+	for {
+		b, err := l.bound()
+		if err != nil {
+			return nil, err
+		}
+		bounds = append(bounds, b)
+
+		if l.punct(')') {
+			return bounds, nil
+		}
+		if !l.punct(',') {
+			return nil, errors.New("expected , or )")
+		}
+	}
+}
+
+func (l *boundLexer) bound() (RangeBound, error) {
+	switch {
+	case l.keyword("MINVALUE"):
+		return RangeBound{Kind: RangeBoundMinValue}, nil
+	case l.keyword("MAXVALUE"):
+		return RangeBound{Kind: RangeBoundMaxValue}, nil
+	}
+
+	l.skipSpace()
+
+	var text string
+	if l.punct('\'') {
+		var sb strings.Builder
+		for {
+			i := strings.IndexByte(l.s, '\'')
+			if i < 0 {
+				return RangeBound{}, errors.New("unterminated quoted string")
+			}
+			sb.WriteString(l.s[:i])
+			l.s = l.s[i+1:]
+			if !strings.HasPrefix(l.s, "'") {
+				break
+			}
+			sb.WriteByte('\'')
+			l.s = l.s[1:]
+		}
+		text = sb.String()
+	} else {
+		n := strings.IndexFunc(l.s, func(r rune) bool {
+			return !strings.ContainsRune("0123456789.eE+-", r)
+		})
+		if n < 0 {
+			n = len(l.s)
+		}
+		text, l.s = l.s[:n], l.s[n:]
+	}
+
+	// Skip a cast such as ::numeric.
+	if l.skipSpace(); strings.HasPrefix(l.s, "::") {
+		l.s = strings.TrimLeftFunc(l.s[2:], func(r rune) bool {
+			return r < 0x80 && isIdentChar(byte(r))
+		})
+	}
+
+	d, err := decimal128.Parse(strings.TrimSpace(text))
+	if err != nil {
+		return RangeBound{}, fmt.Errorf("invalid numeric bound %q", text)
+	}
+
+	return RangeBound{Kind: RangeBoundValue, Value: d}, nil
+}
+
+func isIdentChar(c byte) bool {
+	return c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || isDigit(c)
+}
+
+// RangePartitionRouter finds the partition of a table partitioned by range on
+// numeric columns that a row belongs to, comparing values the way PostgreSQL
+// compares numeric values. The zero value has no partitions.
+type RangePartitionRouter struct {
+	// partitions are the non-default partitions, sorted by lower bound.
+	partitions []RangePartition
+	def        *RangePartition
+	natts      int
+}
+
+// NewRangePartitionRouter returns a router for the given partitions. As
+// PostgreSQL does when the partitions are created, it requires every bound to
+// have one datum per partition key column, every range to be non-empty and
+// the partitions not to overlap. At most one partition may be the default.
+func NewRangePartitionRouter(partitions ...RangePartition) (*RangePartitionRouter, error) {
+	r := &RangePartitionRouter{}
+
+	for _, p := range partitions {
+		if p.Default {
+			if r.def != nil {
+				return nil, fmt.Errorf("partition %q conflicts with existing default partition %q", p.Name, r.def.Name)
+			}
+			r.def = &p
+			continue
+		}
+
+		if r.natts == 0 {
+			r.natts = len(p.From)
+		}
+		if len(p.From) != r.natts || r.natts == 0 {
+			return nil, fmt.Errorf("partition %q: FROM must specify exactly one value per partitioning column", p.Name)
+		}
+		if len(p.To) != r.natts {
+			return nil, fmt.Errorf("partition %q: TO must specify exactly one value per partitioning column", p.Name)
+		}
+		if err := checkRangeBound(p.From); err != nil {
+			return nil, fmt.Errorf("partition %q: %w", p.Name, err)
+		}
+		if err := checkRangeBound(p.To); err != nil {
+			return nil, fmt.Errorf("partition %q: %w", p.Name, err)
+		}
+		if cmpRangeBounds(p.From, p.To) >= 0 {
+			return nil, fmt.Errorf("empty range bound specified for partition %q", p.Name)
+		}
+
+		r.partitions = append(r.partitions, p)
+	}
+
+	slices.SortStableFunc(r.partitions, func(a, b RangePartition) int {
+		return cmpRangeBounds(a.From, b.From)
+	})
+
+	for i := 1; i < len(r.partitions); i++ {
+		if prev := r.partitions[i-1]; cmpRangeBounds(prev.To, r.partitions[i].From) > 0 {
+			return nil, fmt.Errorf("partition %q would overlap partition %q", r.partitions[i].Name, prev.Name)
+		}
+	}
+
+	return r, nil
+}
+
+// checkRangeBound checks that MINVALUE and MAXVALUE are only followed by the
+// same, as PostgreSQL requires.
+func checkRangeBound(bound []RangeBound) error {
+	for i := 1; i < len(bound); i++ {
+		if k := bound[i-1].Kind; k != RangeBoundValue && bound[i].Kind != k {
+			if k == RangeBoundMinValue {
+				return errors.New("every bound following MINVALUE must also be MINVALUE")
+			}
+			return errors.New("every bound following MAXVALUE must also be MAXVALUE")
+		}
+	}
+	return nil
+}
+
+// cmpRangeBounds compares two bounds column by column, like
+// partition_rbound_cmp in PostgreSQL.
+func cmpRangeBounds(a, b []RangeBound) int {
+	for i := range a {
+		if a[i].Kind != b[i].Kind {
+			return int(a[i].Kind - b[i].Kind)
+		}
+		if a[i].Kind != RangeBoundValue {
+			// Both are MINVALUE or both are MAXVALUE, and so are the rest.
+			return 0
+		}
+		if c := cmpNumeric(a[i].Value, b[i].Value); c != 0 {
+			return c
+		}
+	}
+	return 0
+}
+
+// cmpRangeBoundKeys compares a bound with the partition key values of a row,
+// like partition_rbound_datum_cmp in PostgreSQL.
+func cmpRangeBoundKeys(bound []RangeBound, keys []decimal128.Decimal) int {
+	for i, key := range keys {
+		switch bound[i].Kind {
+		case RangeBoundMinValue:
+			return -1
+		case RangeBoundMaxValue:
+			return 1
+		}
+		if c := cmpNumeric(bound[i].Value, key); c != 0 {
+			return c
+		}
+	}
+	return 0
+}
+
+// Route returns the partition that a row with the given partition key values
+// belongs to: the partition whose range includes its lower bound and excludes
+// its upper bound, or else the default partition. Rows with a NULL key value
+// can only go to the default partition. It returns false if no partition
+// accepts the row, in which case PostgreSQL rejects it.
+func (r *RangePartitionRouter) Route(keys ...NullDecimal) (RangePartition, bool) {
+	values := make([]decimal128.Decimal, len(keys))
+	for i, key := range keys {
+		if !key.Valid {
+			return r.defaultPartition()
+		}
+		values[i] = key.Decimal
+	}
+
+	if len(r.partitions) > 0 && len(values) != r.natts {
+		return RangePartition{}, false
+	}
+
+	// Find the last partition whose lower bound is not above the row.
+	i := sort.Search(len(r.partitions), func(i int) bool {
+		return cmpRangeBoundKeys(r.partitions[i].From, values) > 0
+	}) - 1
+	if i >= 0 && cmpRangeBoundKeys(r.partitions[i].To, values) > 0 {
+		return r.partitions[i], true
+	}
+
+	return r.defaultPartition()
+}
+
+func (r *RangePartitionRouter) defaultPartition() (RangePartition, bool) {
+	if r.def == nil {
+		return RangePartition{}, false
+	}
+	return *r.def, true
+}
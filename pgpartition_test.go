@@ -0,0 +1,208 @@
+package decimal_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ingothierack/decimal128"
+	pgxdecimal "github.com/ingothierack/pgx-woodsbury-decimal128"
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/require"
+)
+
+func rangeValue(s string) pgxdecimal.RangeBound {
+	return pgxdecimal.RangeBound{Kind: pgxdecimal.RangeBoundValue, Value: decimal128.MustParse(s)}
+}
+
+var (
+	rangeMin = pgxdecimal.RangeBound{Kind: pgxdecimal.RangeBoundMinValue}
+	rangeMax = pgxdecimal.RangeBound{Kind: pgxdecimal.RangeBoundMaxValue}
+)
+
+func TestParseRangePartitionBound(t *testing.T) {
+	for _, tt := range []struct {
+		bound    string
+		expected pgxdecimal.RangePartition
+	}{
+		{
+			"FOR VALUES FROM ('0') TO ('100')",
+			pgxdecimal.RangePartition{Name: "p", From: []pgxdecimal.RangeBound{rangeValue("0")}, To: []pgxdecimal.RangeBound{rangeValue("100")}},
+		},
+		{
+			"FOR VALUES FROM (MINVALUE) TO ('-5')",
+			pgxdecimal.RangePartition{Name: "p", From: []pgxdecimal.RangeBound{rangeMin}, To: []pgxdecimal.RangeBound{rangeValue("-5")}},
+		},
+		{
+			"FOR VALUES FROM (10.5, MINVALUE) TO ('Infinity', MAXVALUE)",
+			pgxdecimal.RangePartition{
+				Name: "p",
+				From: []pgxdecimal.RangeBound{rangeValue("10.5"), rangeMin},
+				To:   []pgxdecimal.RangeBound{rangeValue("Infinity"), rangeMax},
+			},
+		},
+		{
+			"for values from (1.5e+3::numeric) to (maxvalue)",
+			pgxdecimal.RangePartition{Name: "p", From: []pgxdecimal.RangeBound{rangeValue("1500")}, To: []pgxdecimal.RangeBound{rangeMax}},
+		},
+		{"DEFAULT", pgxdecimal.RangePartition{Name: "p", Default: true}},
+	} {
+		p, err := pgxdecimal.ParseRangePartitionBound("p", tt.bound)
+		require.NoError(t, err, tt.bound)
+		require.Equal(t, len(tt.expected.From), len(p.From), tt.bound)
+		for i := range p.From {
+			require.Equal(t, tt.expected.From[i].Kind, p.From[i].Kind, tt.bound)
+			require.Equal(t, pgxdecimal.Decimal(tt.expected.From[i].Value).String(), pgxdecimal.Decimal(p.From[i].Value).String(), tt.bound)
+			require.Equal(t, tt.expected.To[i].Kind, p.To[i].Kind, tt.bound)
+			require.Equal(t, pgxdecimal.Decimal(tt.expected.To[i].Value).String(), pgxdecimal.Decimal(p.To[i].Value).String(), tt.bound)
+		}
+		require.Equal(t, tt.expected.Default, p.Default, tt.bound)
+	}
+
+	for _, bound := range []string{
+		"FOR VALUES IN ('1', '2')",
+		"FOR VALUES WITH (modulus 4, remainder 0)",
+		"FOR VALUES FROM ('1') TO ('abc')",
+		"FOR VALUES FROM ('1') TO ('2'",
+		"FOR VALUES FROM ('1) TO ('2')",
+		"DEFAULT x",
+	} {
+		_, err := pgxdecimal.ParseRangePartitionBound("p", bound)
+		require.Error(t, err, bound)
+	}
+}
+
+func TestRangePartitionRouter(t *testing.T) {
+	r, err := pgxdecimal.NewRangePartitionRouter(
+		pgxdecimal.RangePartition{Name: "high", From: []pgxdecimal.RangeBound{rangeValue("100")}, To: []pgxdecimal.RangeBound{rangeMax}},
+		pgxdecimal.RangePartition{Name: "low", From: []pgxdecimal.RangeBound{rangeMin}, To: []pgxdecimal.RangeBound{rangeValue("0")}},
+		pgxdecimal.RangePartition{Name: "mid", From: []pgxdecimal.RangeBound{rangeValue("0")}, To: []pgxdecimal.RangeBound{rangeValue("10.5")}},
+		pgxdecimal.RangePartition{Name: "other", Default: true},
+	)
+	require.NoError(t, err)
+
+	for _, tt := range []struct {
+		value    string
+		expected string
+	}{
+		{"-Infinity", "low"},
+		{"-0.001", "low"},
+		{"0", "mid"},
+		{"10.4999", "mid"},
+		{"10.5", "other"},
+		{"99.99", "other"},
+		{"100", "high"},
+		{"Infinity", "high"},
+		{"NaN", "high"},
+	} {
+		p, ok := r.Route(pgxdecimal.NullDecimal{Decimal: decimal128.MustParse(tt.value), Valid: true})
+		require.True(t, ok, tt.value)
+		require.Equal(t, tt.expected, p.Name, tt.value)
+	}
+
+	p, ok := r.Route(pgxdecimal.NullDecimal{})
+	require.True(t, ok)
+	require.Equal(t, "other", p.Name)
+
+	_, ok = (&pgxdecimal.RangePartitionRouter{}).Route(pgxdecimal.NullDecimal{Decimal: decimal128.FromInt64(1), Valid: true})
+	require.False(t, ok)
+}
+
+func TestRangePartitionRouterMultiColumn(t *testing.T) {
+	r, err := pgxdecimal.NewRangePartitionRouter(
+		pgxdecimal.RangePartition{Name: "a", From: []pgxdecimal.RangeBound{rangeValue("0"), rangeMin}, To: []pgxdecimal.RangeBound{rangeValue("0"), rangeValue("5")}},
+		pgxdecimal.RangePartition{Name: "b", From: []pgxdecimal.RangeBound{rangeValue("0"), rangeValue("5")}, To: []pgxdecimal.RangeBound{rangeValue("1"), rangeMin}},
+	)
+	require.NoError(t, err)
+
+	for _, tt := range []struct {
+		a, b     string
+		expected string
+	}{
+		{"0", "-100", "a"},
+		{"0", "4.9", "a"},
+		{"0", "5", "b"},
+		{"0.5", "-100", "b"},
+		{"0.99", "NaN", "b"},
+		{"1", "-100", ""},
+		{"-1", "0", ""},
+	} {
+		p, ok := r.Route(
+			pgxdecimal.NullDecimal{Decimal: decimal128.MustParse(tt.a), Valid: true},
+			pgxdecimal.NullDecimal{Decimal: decimal128.MustParse(tt.b), Valid: true},
+		)
+		require.Equal(t, tt.expected != "", ok, "%s, %s", tt.a, tt.b)
+		require.Equal(t, tt.expected, p.Name, "%s, %s", tt.a, tt.b)
+	}
+}
+
+func TestRangePartitionRouterErrors(t *testing.T) {
+	for _, partitions := range [][]pgxdecimal.RangePartition{
+		{{Name: "p", From: []pgxdecimal.RangeBound{rangeValue("1")}, To: []pgxdecimal.RangeBound{rangeValue("1")}}},
+		{{Name: "p", From: []pgxdecimal.RangeBound{rangeValue("2")}, To: []pgxdecimal.RangeBound{rangeValue("1")}}},
+		{{Name: "p", From: []pgxdecimal.RangeBound{rangeValue("1")}, To: []pgxdecimal.RangeBound{rangeValue("2"), rangeValue("3")}}},
+		{{Name: "p", From: []pgxdecimal.RangeBound{rangeMin, rangeValue("1")}, To: []pgxdecimal.RangeBound{rangeMax, rangeMax}}},
+		{
+			{Name: "p", From: []pgxdecimal.RangeBound{rangeValue("0")}, To: []pgxdecimal.RangeBound{rangeValue("10")}},
+			{Name: "q", From: []pgxdecimal.RangeBound{rangeValue("5")}, To: []pgxdecimal.RangeBound{rangeValue("20")}},
+		},
+		{{Name: "p", Default: true}, {Name: "q", Default: true}},
+	} {
+		_, err := pgxdecimal.NewRangePartitionRouter(partitions...)
+		require.Error(t, err, "%v", partitions)
+	}
+}
+
+func TestRangePartitionRouterMatchServer(t *testing.T) {
+	defaultConnTestRunner.RunTest(context.Background(), t, func(ctx context.Context, t testing.TB, conn *pgx.Conn) {
+		for _, sql := range []string{
+			"create temporary table range_part (a numeric, b numeric) partition by range (a, b)",
+			"create temporary table range_part_low partition of range_part for values from (minvalue, minvalue) to (0, 0)",
+			"create temporary table range_part_mid partition of range_part for values from (0, 0) to (10.5, maxvalue)",
+			"create temporary table range_part_high partition of range_part for values from (100, minvalue) to ('Infinity', maxvalue)",
+			"create temporary table range_part_other partition of range_part default",
+		} {
+			_, err := conn.Exec(ctx, sql)
+			require.NoError(t, err)
+		}
+
+		rows, err := conn.Query(ctx, "select c.relname, pg_get_expr(c.relpartbound, c.oid) from pg_inherits i join pg_class c on c.oid = i.inhrelid where i.inhparent = 'range_part'::regclass")
+		require.NoError(t, err)
+
+		var partitions []pgxdecimal.RangePartition
+		for rows.Next() {
+			var name, bound string
+			require.NoError(t, rows.Scan(&name, &bound))
+
+			p, err := pgxdecimal.ParseRangePartitionBound(name, bound)
+			require.NoError(t, err, bound)
+			partitions = append(partitions, p)
+		}
+		require.NoError(t, rows.Err())
+
+		r, err := pgxdecimal.NewRangePartitionRouter(partitions...)
+		require.NoError(t, err)
+
+		for _, a := range []string{"-Infinity", "-1", "0", "10.5", "50", "100", "Infinity", "NaN", ""} {
+			for _, b := range []string{"-1", "0", "1", "NaN", ""} {
+				var aArg, bArg any
+				keys := make([]pgxdecimal.NullDecimal, 2)
+				if a != "" {
+					aArg = a
+					keys[0] = pgxdecimal.NullDecimal{Decimal: decimal128.MustParse(a), Valid: true}
+				}
+				if b != "" {
+					bArg = b
+					keys[1] = pgxdecimal.NullDecimal{Decimal: decimal128.MustParse(b), Valid: true}
+				}
+
+				var expected string
+				err := conn.QueryRow(ctx, "insert into range_part values ($1::text::numeric, $2::text::numeric) returning tableoid::regclass::text", aArg, bArg).Scan(&expected)
+				require.NoError(t, err)
+
+				p, ok := r.Route(keys...)
+				require.True(t, ok)
+				require.Equal(t, expected, p.Name, "%s, %s", a, b)
+			}
+		}
+	})
+}
@@ -0,0 +1,134 @@
+package decimal
+
+import (
+	"encoding/binary"
+	"math/bits"
+
+	"github.com/ingothierack/decimal128"
+)
+
+// Sign words of the numeric binary format.
+const (
+	numericPos  = 0x0000
+	numericNeg  = 0x4000
+	numericNaN  = 0xc000
+	numericPInf = 0xd000
+	numericNInf = 0xf000
+)
+
+const (
+	numericNBase = 10000
+
+	// numericMaxDig is the number of base 10000 digits of a 37 digit
+	// coefficient.
+	numericMaxDig = 10
+)
+
+// AppendNumericSend appends d in the binary format of numeric_send to buf,
+// without the length word: ndigits, weight, sign and dscale as 16 bit
+// integers followed by the base 10000 digits. The display scale is the scale
+// of d, so 1.50 is sent with dscale 2. Finite values are converted with 128
+// bit integer arithmetic, without allocating.
+func AppendNumericSend(buf []byte, d decimal128.Decimal) []byte {
+	switch {
+	case d.IsNaN():
+		return appendNumericHeader(buf, 0, 0, numericNaN, 0)
+	case d.IsInf(1):
+		return appendNumericHeader(buf, 0, 0, numericPInf, 0)
+	case d.IsInf(-1):
+		return appendNumericHeader(buf, 0, 0, numericNInf, 0)
+	}
+
+	var sigBuf [16]byte
+	_, neg, sig, exp := d.Decompose(sigBuf[:0])
+	hi, lo := uint128FromBytes(sig)
+
+	dscale := 0
+	if exp < 0 {
+		dscale = int(-exp)
+	}
+
+	// Make the exponent a multiple of 4, so that it counts base 10000 digits.
+	// The coefficient stays below 10^37, which fits in 128 bits.
+	r := (int(exp)%decDigits + decDigits) % decDigits
+	hi, lo = mul128(hi, lo, uint64(pow10Uint64[r]))
+	groups := (int(exp) - r) / decDigits
+
+	var digits [numericMaxDig]uint16
+	n := 0
+	for hi|lo != 0 {
+		var rem uint64
+		hi, lo, rem = div128(hi, lo, numericNBase)
+		digits[n] = uint16(rem)
+		n++
+	}
+
+	start := 0
+	for start < n && digits[start] == 0 {
+		start++
+		groups++
+	}
+	if start == n {
+		return appendNumericHeader(buf, 0, 0, numericPos, dscale)
+	}
+
+	sign := numericPos
+	if neg {
+		sign = numericNeg
+	}
+
+	buf = appendNumericHeader(buf, n-start, groups+n-start-1, sign, dscale)
+	for i := n - 1; i >= start; i-- {
+		buf = binary.BigEndian.AppendUint16(buf, digits[i])
+	}
+
+	return buf
+}
+
+func appendNumericHeader(buf []byte, ndigits, weight, sign, dscale int) []byte {
+	buf = binary.BigEndian.AppendUint16(buf, uint16(ndigits))
+	buf = binary.BigEndian.AppendUint16(buf, uint16(int16(weight)))
+	buf = binary.BigEndian.AppendUint16(buf, uint16(sign))
+	return binary.BigEndian.AppendUint16(buf, uint16(dscale))
+}
+
+var pow10Uint64 = [...]uint64{
+	1, 10, 100, 1000, 10000, 100000, 1000000, 10000000, 100000000, 1000000000,
+	10000000000, 100000000000, 1000000000000, 10000000000000, 100000000000000,
+	1000000000000000, 10000000000000000, 100000000000000000, 1000000000000000000,
+	10000000000000000000,
+}
+
+// uint128FromBytes returns the big-endian unsigned integer b of at most 16
+// bytes.
+func uint128FromBytes(b []byte) (hi, lo uint64) {
+	for _, c := range b {
+		hi = hi<<8 | lo>>56
+		lo = lo<<8 | uint64(c)
+	}
+	return hi, lo
+}
+
+func mul128(hi, lo, m uint64) (uint64, uint64) {
+	h, l := bits.Mul64(lo, m)
+	return hi*m + h, l
+}
+
+func div128(hi, lo, d uint64) (qhi, qlo, rem uint64) {
+	qhi, rem = hi/d, hi%d
+	qlo, rem = bits.Div64(rem, lo, d)
+	return qhi, qlo, rem
+}
+
+// less128 reports whether a < b.
+func less128(ahi, alo, bhi, blo uint64) bool {
+	return ahi < bhi || ahi == bhi && alo < blo
+}
+
+// pow10Uint128 returns 10^n for n <= 38.
+func pow10Uint128(n int) (hi, lo uint64) {
+	if n < len(pow10Uint64) {
+		return 0, pow10Uint64[n]
+	}
+	return mul128(0, pow10Uint64[n-len(pow10Uint64)+1], pow10Uint64[len(pow10Uint64)-1])
+}
@@ -0,0 +1,823 @@
+package decimal
+
+import (
+	"bytes"
+	"cmp"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"strings"
+
+	"github.com/ingothierack/decimal128"
+)
+
+// NumericLocale holds the locale dependent symbols used by the D, G, L and S
+// patterns of NumericToChar and NumericToNumber and by MoneyIn, like the
+// fields of C's struct lconv that PostgreSQL reads from lc_numeric and
+// lc_monetary. Empty fields fall back to the defaults PostgreSQL uses, so the
+// zero value behaves like the C locale.
+type NumericLocale struct {
+	// DecimalPoint and ThousandsSep are the lc_numeric separators.
+	DecimalPoint string
+	ThousandsSep string
+
+	// MonDecimalPoint, MonThousandsSep and FracDigits describe money values
+	// in lc_monetary. FracDigits is only used if MonDecimalPoint is set, as
+	// it is in every locale but C, where money has two fraction digits.
+	MonDecimalPoint string
+	MonThousandsSep string
+	FracDigits      int
+
+	CurrencySymbol string
+	PositiveSign   string
+	NegativeSign   string
+}
+
+// Pattern keywords of PostgreSQL's numeric formatting templates.
+const (
+	numComma = iota + 1
+	numDec
+	num0
+	num9
+	numB
+	numC
+	numD
+	numE
+	numFM
+	numG
+	numL
+	numMI
+	numPL
+	numPR
+	numRN
+	numRNLower
+	numSG
+	numSP
+	numS
+	numTH
+	numTHLower
+	numV
+)
+
+// numKeywords is NUM_keywords from formatting.c. Keywords are matched case
+// sensitively, in this order.
+var numKeywords = []struct {
+	name string
+	id   int
+}{
+	{",", numComma}, {".", numDec}, {"0", num0}, {"9", num9},
+	{"B", numB}, {"C", numC}, {"D", numD}, {"EEEE", numE}, {"FM", numFM},
+	{"G", numG}, {"L", numL}, {"MI", numMI}, {"PL", numPL}, {"PR", numPR},
+	{"RN", numRN}, {"SG", numSG}, {"SP", numSP}, {"S", numS}, {"TH", numTH},
+	{"V", numV},
+	{"b", numB}, {"c", numC}, {"d", numD}, {"eeee", numE}, {"fm", numFM},
+	{"g", numG}, {"l", numL}, {"mi", numMI}, {"pl", numPL}, {"pr", numPR},
+	{"rn", numRNLower}, {"sg", numSG}, {"sp", numSP}, {"s", numS},
+	{"th", numTHLower}, {"v", numV},
+}
+
+// Flags of numDesc.
+const (
+	numFDecimal = 1 << iota
+	numFLDecimal
+	numFZero
+	numFBlank
+	numFFillMode
+	numFLSign
+	numFBracket
+	numFMinus
+	numFPlus
+	numFRoman
+	numFMulti
+	numFPlusPost
+	numFMinusPost
+	numFEEEE
+)
+
+const (
+	numLSignNone = iota
+	numLSignPre
+	numLSignPost
+)
+
+// numNode is a parsed template item: a keyword, or literal text if id is 0.
+type numNode struct {
+	id   int
+	text string
+}
+
+// numDesc is NUMDesc from formatting.c.
+type numDesc struct {
+	pre, post   int
+	lsign       int
+	flag        int
+	preLSignNum int
+	multi       int
+	zeroStart   int
+	zeroEnd     int
+	needLocale  bool
+}
+
+func (n *numDesc) is(flag int) bool {
+	return n.flag&flag != 0
+}
+
+// NumericToChar formats d using a PostgreSQL numeric formatting template,
+// like to_char(numeric, text) in the C locale. It supports the 9, 0, ., ,, D,
+// G, S, PR, MI, PL, SG, FM, EEEE, V, L, RN and TH patterns; other characters
+// are copied to the output, and double-quoted text is copied literally.
+func NumericToChar(d decimal128.Decimal, format string) (string, error) {
+	return NumericToCharLocale(d, format, NumericLocale{})
+}
+
+// NumericToCharLocale is like NumericToChar but takes the decimal point,
+// thousands separator, currency symbol and signs from loc.
+func NumericToCharLocale(d decimal128.Decimal, format string, loc NumericLocale) (string, error) {
+	if format == "" {
+		return "", nil
+	}
+
+	nodes, num, err := parseNumFormat(format)
+	if err != nil {
+		return "", err
+	}
+
+	var (
+		numstr       string
+		outPreSpaces int
+		sign         byte
+	)
+
+	switch {
+	case num.is(numFRoman):
+		numstr = intToRoman(roundToInt32(d))
+
+	case num.is(numFEEEE):
+		orgnum := numericOutSci(d, num.post)
+		switch {
+		case d.IsNaN() || d.IsInf(0):
+			// Leave room for the sign, the decimal point, "e", the sign of
+			// the exponent and two exponent digits.
+			b := bytes.Repeat([]byte{'#'}, num.pre+num.post+6)
+			b[0] = ' '
+			b[num.pre+1] = '.'
+			numstr = string(b)
+		case orgnum[0] != '-':
+			numstr = " " + orgnum
+		default:
+			numstr = orgnum
+		}
+
+	default:
+		var orgnum string
+		if d.IsNaN() || d.IsInf(0) {
+			orgnum = NumericOut(d)
+		} else {
+			v := newNumericVar(d)
+			if num.is(numFMulti) {
+				v = mulVar(v, numericVar{coef: pow10(num.multi)})
+				num.pre += num.multi
+			}
+			orgnum = string(roundVar(v, num.post, true).appendText(nil))
+		}
+
+		if orgnum[0] == '-' {
+			sign = '-'
+			numstr = orgnum[1:]
+		} else {
+			sign = '+'
+			numstr = orgnum
+		}
+
+		preLen := len(numstr)
+		if i := strings.IndexByte(numstr, '.'); i >= 0 {
+			preLen = i
+		}
+
+		if preLen < num.pre {
+			outPreSpaces = num.pre - preLen
+		} else if preLen > num.pre {
+			// The value does not fit the template.
+			b := bytes.Repeat([]byte{'#'}, num.pre+num.post+1)
+			b[num.pre] = '.'
+			numstr = string(b)
+		}
+	}
+
+	return numToChar(nodes, num, loc, numstr, outPreSpaces, sign)
+}
+
+// parseNumFormat is parse_format and NUMDesc_prepare from formatting.c for
+// numeric templates.
+func parseNumFormat(format string) ([]numNode, *numDesc, error) {
+	var nodes []numNode
+	num := &numDesc{}
+
+	for s := format; s != ""; {
+		if id, n := matchNumKeyword(s); id != 0 {
+			if err := num.prepare(id); err != nil {
+				return nil, nil, err
+			}
+			nodes = append(nodes, numNode{id: id})
+			s = s[n:]
+			continue
+		}
+
+		// Double-quoted text is copied literally, with backslash quoting
+		// the next character.
+		if s[0] == '"' {
+			s = s[1:]
+			for s != "" {
+				if s[0] == '"' {
+					s = s[1:]
+					break
+				}
+				if s[0] == '\\' && len(s) > 1 {
+					s = s[1:]
+				}
+				n := charLen(s)
+				nodes = append(nodes, numNode{text: s[:n]})
+				s = s[n:]
+			}
+			continue
+		}
+
+		// Outside quotes, backslash only escapes a double quote.
+		if strings.HasPrefix(s, `\"`) {
+			s = s[1:]
+		}
+		n := charLen(s)
+		nodes = append(nodes, numNode{text: s[:n]})
+		s = s[n:]
+	}
+
+	return nodes, num, nil
+}
+
+func matchNumKeyword(s string) (int, int) {
+	for _, kw := range numKeywords {
+		if strings.HasPrefix(s, kw.name) {
+			return kw.id, len(kw.name)
+		}
+	}
+
+	return 0, 0
+}
+
+// charLen returns the length in bytes of the first UTF-8 character of s.
+func charLen(s string) int {
+	for i := range s {
+		if i > 0 {
+			return i
+		}
+	}
+
+	return len(s)
+}
+
+// prepare is NUMDesc_prepare from formatting.c.
+func (n *numDesc) prepare(id int) error {
+	if n.is(numFEEEE) && id != numE {
+		return errors.New(`"EEEE" must be the last pattern used`)
+	}
+
+	switch id {
+	case num9:
+		if n.is(numFBracket) {
+			return errors.New(`"9" must be ahead of "PR"`)
+		}
+		switch {
+		case n.is(numFMulti):
+			n.multi++
+		case n.is(numFDecimal):
+			n.post++
+		default:
+			n.pre++
+		}
+
+	case num0:
+		if n.is(numFBracket) {
+			return errors.New(`"0" must be ahead of "PR"`)
+		}
+		if !n.is(numFZero) && !n.is(numFDecimal) {
+			n.flag |= numFZero
+			n.zeroStart = n.pre + 1
+		}
+		if !n.is(numFDecimal) {
+			n.pre++
+		} else {
+			n.post++
+		}
+		n.zeroEnd = n.pre + n.post
+
+	case numB:
+		if n.pre == 0 && n.post == 0 && !n.is(numFZero) {
+			n.flag |= numFBlank
+		}
+
+	case numD, numDec:
+		if id == numD {
+			n.flag |= numFLDecimal
+			n.needLocale = true
+		}
+		if n.is(numFDecimal) {
+			return errors.New("multiple decimal points")
+		}
+		if n.is(numFMulti) {
+			return errors.New(`cannot use "V" and decimal point together`)
+		}
+		n.flag |= numFDecimal
+
+	case numFM:
+		n.flag |= numFFillMode
+
+	case numS:
+		if n.is(numFLSign) {
+			return errors.New(`cannot use "S" twice`)
+		}
+		if n.is(numFPlus | numFMinus | numFBracket) {
+			return errors.New(`cannot use "S" and "PL"/"MI"/"SG"/"PR" together`)
+		}
+		if !n.is(numFDecimal) {
+			n.lsign = numLSignPre
+			n.preLSignNum = n.pre
+			n.needLocale = true
+			n.flag |= numFLSign
+		} else if n.lsign == numLSignNone {
+			n.lsign = numLSignPost
+			n.needLocale = true
+			n.flag |= numFLSign
+		}
+
+	case numMI:
+		if n.is(numFLSign) {
+			return errors.New(`cannot use "S" and "MI" together`)
+		}
+		n.flag |= numFMinus
+		if n.is(numFDecimal) {
+			n.flag |= numFMinusPost
+		}
+
+	case numPL:
+		if n.is(numFLSign) {
+			return errors.New(`cannot use "S" and "PL" together`)
+		}
+		n.flag |= numFPlus
+		if n.is(numFDecimal) {
+			n.flag |= numFPlusPost
+		}
+
+	case numSG:
+		if n.is(numFLSign) {
+			return errors.New(`cannot use "S" and "SG" together`)
+		}
+		n.flag |= numFMinus | numFPlus
+
+	case numPR:
+		if n.is(numFLSign | numFPlus | numFMinus) {
+			return errors.New(`cannot use "PR" and "S"/"PL"/"MI"/"SG" together`)
+		}
+		n.flag |= numFBracket
+
+	case numRN, numRNLower:
+		n.flag |= numFRoman
+
+	case numL, numG:
+		n.needLocale = true
+
+	case numV:
+		if n.is(numFDecimal) {
+			return errors.New(`cannot use "V" and decimal point together`)
+		}
+		n.flag |= numFMulti
+
+	case numE:
+		if n.is(numFEEEE) {
+			return errors.New(`cannot use "EEEE" twice`)
+		}
+		if n.is(numFBlank | numFFillMode | numFLSign | numFBracket | numFMinus | numFPlus | numFRoman | numFMulti) {
+			return errors.New(`"EEEE" is incompatible with other formats`)
+		}
+		n.flag |= numFEEEE
+	}
+
+	return nil
+}
+
+// numProc is the state of NUM_processor from formatting.c.
+type numProc struct {
+	num *numDesc
+	out []byte
+
+	sign      byte
+	signWrote bool
+	numCount  int
+	numIn     bool
+	numCurr   int
+
+	outPreSpaces int
+	number       string
+	numberP      int
+	lastRelevant int
+
+	negativeSign   string
+	positiveSign   string
+	decimal        string
+	thousandsSep   string
+	currencySymbol string
+}
+
+// numToChar is the to_char part of NUM_processor from formatting.c.
+func numToChar(nodes []numNode, num *numDesc, loc NumericLocale, number string, outPreSpaces int, sign byte) (string, error) {
+	p := &numProc{num: num, number: number, lastRelevant: -1}
+
+	if num.zeroStart > 0 {
+		num.zeroStart--
+	}
+
+	if num.is(numFEEEE) {
+		return number, nil
+	}
+
+	p.sign = sign
+	if num.is(numFPlus) || num.is(numFMinus) {
+		// MI, PL and SG write the sign themselves.
+		p.signWrote = !(num.is(numFPlus) && !num.is(numFMinus))
+	} else {
+		if p.sign != '-' && num.is(numFFillMode) {
+			num.flag &^= numFBracket
+		}
+
+		p.signWrote = p.sign == '+' && num.is(numFFillMode) && !num.is(numFLSign)
+
+		if num.lsign == numLSignPre && num.pre == num.preLSignNum {
+			num.lsign = numLSignPost
+		}
+	}
+
+	p.numCount = num.post + num.pre - 1
+	p.outPreSpaces = outPreSpaces
+
+	if num.is(numFFillMode) && num.is(numFDecimal) {
+		p.lastRelevant = lastRelevantDecNum(number)
+
+		// Don't strip digits written by 0 patterns.
+		if p.lastRelevant >= 0 && num.zeroEnd > p.outPreSpaces {
+			lastZero := min(len(number)-1, num.zeroEnd-p.outPreSpaces)
+			p.lastRelevant = max(p.lastRelevant, lastZero)
+		}
+	}
+
+	if !p.signWrote && p.outPreSpaces == 0 {
+		p.numCount++
+	}
+
+	p.prepareLocale(loc)
+
+	for _, n := range nodes {
+		if n.id == 0 {
+			p.out = append(p.out, n.text...)
+			continue
+		}
+
+		switch n.id {
+		case num9, num0, numDec, numD:
+			p.numpartToChar(n.id)
+
+		case numComma:
+			switch {
+			case p.numIn:
+				p.out = append(p.out, ',')
+			case !num.is(numFFillMode):
+				p.out = append(p.out, ' ')
+			}
+
+		case numG:
+			switch {
+			case p.numIn:
+				p.out = append(p.out, p.thousandsSep...)
+			case !num.is(numFFillMode):
+				p.out = append(p.out, strings.Repeat(" ", len([]rune(p.thousandsSep)))...)
+			}
+
+		case numL:
+			p.out = append(p.out, p.currencySymbol...)
+
+		case numRN, numRNLower:
+			s := p.number[p.numberP:]
+			if n.id == numRNLower {
+				s = strings.ToLower(s)
+			}
+			if !num.is(numFFillMode) {
+				s = fmt.Sprintf("%15s", s)
+			}
+			p.out = append(p.out, s...)
+
+		case numTH, numTHLower:
+			if num.is(numFRoman) || p.number[0] == '#' || p.sign == '-' || num.is(numFDecimal) {
+				continue
+			}
+			th, err := ordinalSuffix(p.number)
+			if err != nil {
+				return "", err
+			}
+			if n.id == numTHLower {
+				th = strings.ToLower(th)
+			}
+			p.out = append(p.out, th...)
+
+		case numMI:
+			switch {
+			case p.sign == '-':
+				p.out = append(p.out, '-')
+			case !num.is(numFFillMode):
+				p.out = append(p.out, ' ')
+			}
+
+		case numPL:
+			switch {
+			case p.sign == '+':
+				p.out = append(p.out, '+')
+			case !num.is(numFFillMode):
+				p.out = append(p.out, ' ')
+			}
+
+		case numSG:
+			p.out = append(p.out, p.sign)
+		}
+	}
+
+	// Like the C implementation, the output ends at the first NUL written.
+	if i := bytes.IndexByte(p.out, 0); i >= 0 {
+		p.out = p.out[:i]
+	}
+
+	return string(p.out), nil
+}
+
+// prepareLocale is NUM_prepare_locale from formatting.c.
+func (p *numProc) prepareLocale(loc NumericLocale) {
+	if !p.num.needLocale {
+		loc = NumericLocale{}
+	}
+
+	p.negativeSign = cmp.Or(loc.NegativeSign, "-")
+	p.positiveSign = cmp.Or(loc.PositiveSign, "+")
+
+	p.decimal = "."
+	if p.num.is(numFLDecimal) {
+		p.decimal = cmp.Or(loc.DecimalPoint, ".")
+	}
+
+	// Make sure the thousands separator differs from the decimal point.
+	switch {
+	case loc.ThousandsSep != "":
+		p.thousandsSep = loc.ThousandsSep
+	case p.decimal != ",":
+		p.thousandsSep = ","
+	default:
+		p.thousandsSep = "."
+	}
+
+	p.currencySymbol = cmp.Or(loc.CurrencySymbol, " ")
+}
+
+func (p *numProc) cur() byte {
+	if p.numberP < len(p.number) {
+		return p.number[p.numberP]
+	}
+
+	return 0
+}
+
+func (p *numProc) lastRelevantIsDec() bool {
+	return p.lastRelevant >= 0 && p.number[p.lastRelevant] == '.'
+}
+
+// predecSpace is IS_PREDEC_SPACE from formatting.c: the leading zero of a
+// value below 1, which is written as a space.
+func (p *numProc) predecSpace() bool {
+	return !p.num.is(numFZero) && p.numberP == 0 && p.number[0] == '0' && p.num.post != 0
+}
+
+// numpartToChar is NUM_numpart_to_char from formatting.c.
+func (p *numProc) numpartToChar(id int) {
+	num := p.num
+	if num.is(numFRoman) {
+		return
+	}
+
+	// Write the sign before the first digit.
+	if !p.signWrote &&
+		(p.numCurr >= p.outPreSpaces || num.is(numFZero) && num.zeroStart == p.numCurr) &&
+		(!p.predecSpace() || p.lastRelevantIsDec()) {
+		switch {
+		case num.is(numFLSign):
+			if num.lsign == numLSignPre {
+				p.out = append(p.out, p.localeSign()...)
+				p.signWrote = true
+			}
+		case num.is(numFBracket):
+			p.out = append(p.out, p.bracket('<'))
+			p.signWrote = true
+		case p.sign == '+':
+			if !num.is(numFFillMode) {
+				p.out = append(p.out, ' ')
+			}
+			p.signWrote = true
+		case p.sign == '-':
+			p.out = append(p.out, '-')
+			p.signWrote = true
+		}
+	}
+
+	switch {
+	case p.numCurr < p.outPreSpaces && (num.zeroStart > p.numCurr || !num.is(numFZero)):
+		if !num.is(numFFillMode) {
+			p.out = append(p.out, ' ')
+		}
+
+	case num.is(numFZero) && p.numCurr < p.outPreSpaces && num.zeroStart <= p.numCurr:
+		p.out = append(p.out, '0')
+		p.numIn = true
+
+	default:
+		if p.cur() == '.' {
+			if !p.lastRelevantIsDec() || num.is(numFFillMode) {
+				p.out = append(p.out, p.decimal...)
+			}
+		} else {
+			switch {
+			case p.lastRelevant >= 0 && p.numberP > p.lastRelevant && id != num0:
+				// Trailing zeros are dropped in fill mode.
+			case p.predecSpace():
+				if !num.is(numFFillMode) {
+					p.out = append(p.out, ' ')
+				} else if p.lastRelevantIsDec() {
+					p.out = append(p.out, '0')
+				}
+			default:
+				p.out = append(p.out, p.cur())
+				p.numIn = true
+			}
+		}
+
+		if p.cur() != 0 {
+			p.numberP++
+		}
+	}
+
+	end := p.numCount
+	if p.outPreSpaces > 0 {
+		end++
+	}
+	if num.is(numFDecimal) {
+		end++
+	}
+	if p.lastRelevant >= 0 && p.lastRelevant == p.numberP {
+		end = p.numCurr
+	}
+
+	if p.numCurr+1 == end {
+		switch {
+		case p.signWrote && num.is(numFBracket):
+			p.out = append(p.out, p.bracket('>'))
+		case num.is(numFLSign) && num.lsign == numLSignPost:
+			p.out = append(p.out, p.localeSign()...)
+		}
+	}
+
+	p.numCurr++
+}
+
+// bracket returns b for a negative value and a space otherwise, for PR.
+func (p *numProc) bracket(b byte) byte {
+	if p.sign == '+' {
+		return ' '
+	}
+
+	return b
+}
+
+func (p *numProc) localeSign() string {
+	if p.sign == '-' {
+		return p.negativeSign
+	}
+
+	return p.positiveSign
+}
+
+// lastRelevantDecNum is get_last_relevant_decnum from formatting.c. It returns
+// the index of the last non-zero digit after the decimal point, or of the
+// decimal point itself, or -1 if number has no decimal point.
+func lastRelevantDecNum(number string) int {
+	i := strings.IndexByte(number, '.')
+	if i < 0 {
+		return -1
+	}
+
+	result := i
+	for j := i + 1; j < len(number); j++ {
+		if number[j] != '0' {
+			result = j
+		}
+	}
+
+	return result
+}
+
+// ordinalSuffix is get_th from formatting.c.
+func ordinalSuffix(number string) (string, error) {
+	last := number[len(number)-1]
+	if last < '0' || last > '9' {
+		return "", fmt.Errorf("%q is not a number", number)
+	}
+
+	// All teens get TH.
+	if len(number) > 1 && number[len(number)-2] == '1' {
+		return "TH", nil
+	}
+
+	switch last {
+	case '1':
+		return "ST", nil
+	case '2':
+		return "ND", nil
+	case '3':
+		return "RD", nil
+	}
+
+	return "TH", nil
+}
+
+// roundToInt32 rounds d half away from zero to an int32, returning
+// math.MaxInt32 if d is NaN, infinite or out of range, as to_char does for RN.
+func roundToInt32(d decimal128.Decimal) int {
+	if d.IsNaN() || d.IsInf(0) {
+		return math.MaxInt32
+	}
+
+	v := roundVar(newNumericVar(d), 0, true)
+	if !v.coef.IsInt64() || v.coef.Int64() < math.MinInt32 || v.coef.Int64() > math.MaxInt32 {
+		return math.MaxInt32
+	}
+
+	return int(v.coef.Int64())
+}
+
+// intToRoman is int_to_roman from formatting.c. Values outside 1 to 3999 are
+// written as 15 '#' characters.
+func intToRoman(number int) string {
+	if number < 1 || number > 3999 {
+		return strings.Repeat("#", 15)
+	}
+
+	rm1 := []string{"I", "II", "III", "IV", "V", "VI", "VII", "VIII", "IX"}
+	rm10 := []string{"X", "XX", "XXX", "XL", "L", "LX", "LXX", "LXXX", "XC"}
+	rm100 := []string{"C", "CC", "CCC", "CD", "D", "DC", "DCC", "DCCC", "CM"}
+
+	var b strings.Builder
+	b.WriteString(strings.Repeat("M", number/1000))
+	for _, d := range []struct {
+		digit int
+		rm    []string
+	}{
+		{number / 100 % 10, rm100},
+		{number / 10 % 10, rm10},
+		{number % 10, rm1},
+	} {
+		if d.digit > 0 {
+			b.WriteString(d.rm[d.digit-1])
+		}
+	}
+
+	return b.String()
+}
+
+// numericOutSci is numeric_out_sci from numeric.c: d in exponent notation with
+// scale digits after the decimal point of the significand.
+func numericOutSci(d decimal128.Decimal, scale int) string {
+	if d.IsNaN() || d.IsInf(0) {
+		return NumericOut(d)
+	}
+
+	v := newNumericVar(d)
+	scale = max(scale, 0)
+
+	// The exponent of v with one significant digit before the decimal point.
+	exponent := 0
+	if v.coef.Sign() != 0 {
+		exponent = len(new(big.Int).Abs(v.coef).String()) - 1 - v.scale
+	}
+
+	var pow numericVar
+	if exponent >= 0 {
+		pow = numericVar{coef: pow10(exponent)}
+	} else {
+		pow = numericVar{coef: big.NewInt(1), scale: -exponent}
+	}
+
+	sig := divVar(v, pow, scale, true)
+	return fmt.Sprintf("%se%+03d", sig.appendText(nil), exponent)
+}
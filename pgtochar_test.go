@@ -0,0 +1,119 @@
+package decimal_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ingothierack/decimal128"
+	pgxdecimal "github.com/ingothierack/pgx-woodsbury-decimal128"
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/require"
+)
+
+// toCharTests holds the output of PostgreSQL for SELECT to_char(x::numeric,
+// format) in the C locale. TestNumericToCharMatchServer checks them against a
+// live server.
+var toCharTests = []struct {
+	value    string
+	format   string
+	expected string
+}{
+	{"-0.1", "99.99", "  -.10"},
+	{"-0.1", "FM9.99", "-.1"},
+	{"-0.1", "FM90.99", "-0.1"},
+	{"0.1", "0.9", " 0.1"},
+	{"12", "9990999.9", "    0012.0"},
+	{"12", "FM9990999.9", "0012."},
+	{"485", "999", " 485"},
+	{"-485", "999", "-485"},
+	{"485", "9 9 9", " 4 8 5"},
+	{"1485", "9,999", " 1,485"},
+	{"1485", "9G999", " 1,485"},
+	{"148.5", "999.999", " 148.500"},
+	{"148.5", "FM999.999", "148.5"},
+	{"148.5", "FM999.990", "148.500"},
+	{"148.5", "999D999", " 148.500"},
+	{"-485", "999S", "485-"},
+	{"-485", "999MI", "485-"},
+	{"485", "999MI", "485 "},
+	{"485", "FM999MI", "485"},
+	{"485", "PL999", "+ 485"},
+	{"485", "SG999", "+485"},
+	{"-485", "SG999", "-485"},
+	{"-485", "9SG99", "4-85"},
+	{"-485", "999PR", "<485>"},
+	{"485", "L999", "  485"},
+	{"485", "RN", "        CDLXXXV"},
+	{"485", "FMRN", "CDLXXXV"},
+	{"5.2", "FMRN", "V"},
+	{"3999", "rn", "      mmmcmxcix"},
+	{"4000", "RN", "###############"},
+	{"482", "999th", " 482nd"},
+	{"485", `"Good number:"999`, "Good number: 485"},
+	{"485.8", `"Pre:"999" Post:" .999`, "Pre: 485 Post: .800"},
+	{"12", "99V999", " 12000"},
+	{"12.4", "99V999", " 12400"},
+	{"12.45", "99V9", " 125"},
+	{"0.0004859", "9.99EEEE", " 4.86e-04"},
+	{"-1234.5", "9.9EEEE", "-1.2e+03"},
+	{"1234567.891", "FM999G999G990D00", "1,234,567.89"},
+	{"0.5", "FM999G999G990D00", "0.50"},
+	{"-1234.5", "FM999G999G990D00", "-1,234.50"},
+	{"1234.5", "999G999G990D00", "       1,234.50"},
+	{"1000", "999", " ###"},
+	{"1000", "999.99", " ###.##"},
+	{"NaN", "9.99EEEE", " #.######"},
+	{"-0", "S999", "  +0"},
+	{"0", "", ""},
+}
+
+func TestNumericToChar(t *testing.T) {
+	for _, tt := range toCharTests {
+		s, err := pgxdecimal.NumericToChar(decimal128.MustParse(tt.value), tt.format)
+		require.NoError(t, err, "%s %s", tt.value, tt.format)
+		require.Equal(t, tt.expected, s, "%s %s", tt.value, tt.format)
+	}
+}
+
+func TestNumericToCharLocale(t *testing.T) {
+	// Examples from the PostgreSQL documentation, for a locale like de_DE.
+	loc := pgxdecimal.NumericLocale{DecimalPoint: ",", ThousandsSep: " ", CurrencySymbol: "DM"}
+
+	for _, tt := range []struct {
+		value    string
+		format   string
+		expected string
+	}{
+		{"148.5", "999D999", " 148,500"},
+		{"3148.5", "9G999D999", " 3 148,500"},
+		{"3148.5", "9,999.999", " 3,148.500"},
+		{"485", "L999", "DM 485"},
+		{"-485", "S999", "-485"},
+	} {
+		s, err := pgxdecimal.NumericToCharLocale(decimal128.MustParse(tt.value), tt.format, loc)
+		require.NoError(t, err, "%s %s", tt.value, tt.format)
+		require.Equal(t, tt.expected, s, "%s %s", tt.value, tt.format)
+	}
+
+	s, err := pgxdecimal.NumericToCharLocale(decimal128.MustParse("1234.5"), "9G999D9", pgxdecimal.NumericLocale{DecimalPoint: ","})
+	require.NoError(t, err)
+	require.Equal(t, " 1.234,5", s)
+}
+
+func TestNumericToCharErrors(t *testing.T) {
+	for _, format := range []string{"9.9.9", "99V9.9", "99PR9", "S99S", "S99MI", "PR99SG", "9.9EEEE9", "FM9.9EEEE", "9EEEEEEEE"} {
+		_, err := pgxdecimal.NumericToChar(decimal128.FromInt64(1), format)
+		require.Error(t, err, format)
+	}
+}
+
+func TestNumericToCharMatchServer(t *testing.T) {
+	defaultConnTestRunner.RunTest(context.Background(), t, func(ctx context.Context, t testing.TB, conn *pgx.Conn) {
+		for _, tt := range toCharTests {
+			var result string
+			err := conn.QueryRow(ctx, "select to_char($1::numeric, $2)", tt.value, tt.format).Scan(&result)
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, result, "%s %s", tt.value, tt.format)
+		}
+	})
+}
@@ -0,0 +1,436 @@
+package decimal
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ingothierack/decimal128"
+)
+
+var errNumericFieldOverflow = errors.New("numeric field overflow")
+
+// NumericToNumber parses s using a PostgreSQL numeric formatting template,
+// like to_number(text, text) in the C locale. It accepts exactly the input
+// to_number accepts, including its leniency: characters of s that the
+// template does not consume are ignored. The result is NULL if format is
+// empty, as in PostgreSQL.
+func NumericToNumber(s, format string) (NullDecimal, error) {
+	return NumericToNumberLocale(s, format, NumericLocale{})
+}
+
+// NumericToNumberLocale is like NumericToNumber but takes the decimal point,
+// thousands separator, currency symbol and signs from loc.
+func NumericToNumberLocale(s, format string, loc NumericLocale) (NullDecimal, error) {
+	if format == "" {
+		return NullDecimal{}, nil
+	}
+
+	nodes, num, err := parseNumFormat(format)
+	if err != nil {
+		return NullDecimal{}, err
+	}
+
+	switch {
+	case num.is(numFRoman):
+		return NullDecimal{}, errors.New(`"RN" not supported for input`)
+	case num.is(numFEEEE):
+		return NullDecimal{}, errors.New(`"EEEE" not supported for input`)
+	}
+
+	numstr := numFromChar(nodes, num, loc, s)
+
+	// numeric_in with the precision and scale implied by the template.
+	v, ok := parseNumericText(numstr)
+	if !ok {
+		return NullDecimal{}, fmt.Errorf("invalid input syntax for type numeric: %q", numstr)
+	}
+
+	v, err = applyTypmod(v, num.pre+num.multi+num.post, num.post)
+	if err != nil {
+		return NullDecimal{}, err
+	}
+
+	if num.is(numFMulti) {
+		d, err := v.decimal()
+		if err != nil {
+			return NullDecimal{}, err
+		}
+
+		x, err := NumericPower(decimal128.FromInt64(10), decimal128.FromInt64(int64(-num.multi)))
+		if err != nil {
+			return NullDecimal{}, err
+		}
+
+		d, err = NumericMul(d, x)
+		if err != nil {
+			return NullDecimal{}, err
+		}
+
+		return NullDecimal{Decimal: d, Valid: true}, nil
+	}
+
+	return nullDecimalResult(v)
+}
+
+// numParser is the state of the to_number part of NUM_processor from
+// formatting.c.
+type numParser struct {
+	*numProc
+
+	in       string
+	pos      int
+	number   []byte
+	readPre  int
+	readPost int
+	readDec  bool
+}
+
+// numFromChar is the to_number part of NUM_processor from formatting.c. It
+// returns the number read from s as text for numeric_in, with the sign or a
+// space in front, and sets num.post to the number of fractional digits read.
+func numFromChar(nodes []numNode, num *numDesc, loc NumericLocale, s string) string {
+	p := &numParser{numProc: &numProc{num: num}, in: s, number: []byte{' '}}
+
+	p.prepareLocale(loc)
+
+	for _, n := range nodes {
+		if p.overload() {
+			break
+		}
+
+		// Each literal template character skips one input character,
+		// whether or not they match.
+		if n.id == 0 {
+			p.pos += charLen(p.in[p.pos:])
+			continue
+		}
+
+		switch n.id {
+		case num9, num0, numDec, numD:
+			p.numpartFromChar(n.id)
+
+		case numComma:
+			if !p.numIn && num.is(numFFillMode) {
+				continue
+			}
+
+		case numG, numL:
+			if !p.numIn && num.is(numFFillMode) && n.id == numG {
+				continue
+			}
+
+			pattern := p.thousandsSep
+			if n.id == numL {
+				pattern = p.currencySymbol
+			}
+
+			// Separators and currency symbols may contain data characters,
+			// so they are only skipped if the input matches them.
+			if !p.amount(len(pattern)) || !strings.HasPrefix(p.in[p.pos:], pattern) {
+				continue
+			}
+			p.pos += len(pattern) - 1
+
+		case numTH, numTHLower:
+			if num.is(numFDecimal) {
+				continue
+			}
+			p.pos++
+
+		case numMI, numPL, numSG:
+			c := p.in[p.pos]
+			switch {
+			case c == '-' && n.id != numPL:
+				p.number[0] = '-'
+			case c == '+' && n.id != numMI:
+				p.number[0] = '+'
+			default:
+				p.eatNonDataChars(1)
+				continue
+			}
+
+		default:
+			continue
+		}
+
+		p.pos++
+	}
+
+	if p.number[len(p.number)-1] == '.' {
+		p.number = p.number[:len(p.number)-1]
+	}
+
+	num.post = p.readPost
+	return string(p.number)
+}
+
+// overload is OVERLOAD_TEST from formatting.c: whether all input was read.
+func (p *numParser) overload() bool {
+	return p.pos >= len(p.in)
+}
+
+// amount is AMOUNT_TEST from formatting.c: whether n more bytes can be read.
+func (p *numParser) amount(n int) bool {
+	return p.pos <= len(p.in)-n
+}
+
+func (p *numParser) hasPrefix(s string) bool {
+	return s != "" && p.amount(len(s)) && strings.HasPrefix(p.in[p.pos:], s)
+}
+
+// eatNonDataChars is NUM_eat_non_data_chars from formatting.c.
+func (p *numParser) eatNonDataChars(n int) {
+	for ; n > 0 && !p.overload(); n-- {
+		if strings.IndexByte("0123456789.,+-", p.in[p.pos]) >= 0 {
+			break
+		}
+		p.pos += charLen(p.in[p.pos:])
+	}
+}
+
+// numpartFromChar is NUM_numpart_from_char from formatting.c.
+func (p *numParser) numpartFromChar(id int) {
+	num := p.num
+
+	for range 2 {
+		if p.in[p.pos] == ' ' {
+			p.pos++
+		}
+		if p.overload() {
+			return
+		}
+	}
+
+	// Read the sign before the number.
+	if p.number[0] == ' ' && (id == num0 || id == num9) && p.readPre+p.readPost == 0 {
+		if num.is(numFLSign) && num.lsign == numLSignPre {
+			if p.hasPrefix(p.negativeSign) {
+				p.pos += len(p.negativeSign)
+				p.number[0] = '-'
+			} else if p.hasPrefix(p.positiveSign) {
+				p.pos += len(p.positiveSign)
+				p.number[0] = '+'
+			}
+		} else {
+			switch c := p.in[p.pos]; {
+			case c == '-' || num.is(numFBracket) && c == '<':
+				p.number[0] = '-'
+				p.pos++
+			case c == '+':
+				p.number[0] = '+'
+				p.pos++
+			}
+		}
+	}
+
+	if p.overload() {
+		return
+	}
+
+	// Read a digit or the decimal point.
+	isread := false
+	if c := p.in[p.pos]; isDigit(c) {
+		if p.readDec && p.readPost == num.post {
+			return
+		}
+
+		p.number = append(p.number, c)
+		if p.readDec {
+			p.readPost++
+		} else {
+			p.readPre++
+		}
+		isread = true
+	} else if num.is(numFDecimal) && !p.readDec && p.hasPrefix(p.decimal) {
+		p.pos += len(p.decimal) - 1
+		p.number = append(p.number, '.')
+		p.readDec = true
+		isread = true
+	}
+
+	if p.overload() {
+		return
+	}
+
+	// Read a sign behind the last digit, as in "FM9.999999MI" for "5.01-".
+	if p.number[0] == ' ' && p.readPre+p.readPost > 0 {
+		if num.is(numFLSign) && isread && p.pos+1 < len(p.in) && !isDigit(p.in[p.pos+1]) {
+			tmp := p.pos
+			p.pos++
+			if p.hasPrefix(p.negativeSign) {
+				p.pos += len(p.negativeSign) - 1
+				p.number[0] = '-'
+			} else if p.hasPrefix(p.positiveSign) {
+				p.pos += len(p.positiveSign) - 1
+				p.number[0] = '+'
+			}
+			if p.number[0] == ' ' {
+				p.pos = tmp
+			}
+		} else if !isread && !num.is(numFLSign) && num.is(numFPlus|numFMinus) {
+			if c := p.in[p.pos]; c == '-' || c == '+' {
+				p.number[0] = c
+			}
+		}
+	}
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+// parseNumericText parses the sign, digits and decimal point accepted by
+// numeric_in, after leading and trailing spaces.
+func parseNumericText(s string) (numericVar, bool) {
+	s = strings.TrimSpace(s)
+
+	neg := false
+	if s != "" && (s[0] == '-' || s[0] == '+') {
+		neg = s[0] == '-'
+		s = s[1:]
+	}
+
+	intPart, frac, hasDot := strings.Cut(s, ".")
+	digits := intPart + frac
+	if digits == "" || strings.Trim(digits, "0123456789") != "" || hasDot && strings.Contains(frac, ".") {
+		return numericVar{}, false
+	}
+
+	coef, _ := new(big.Int).SetString(digits, 10)
+	if neg {
+		coef.Neg(coef)
+	}
+
+	return numericVar{coef: coef, scale: len(frac)}, true
+}
+
+// applyTypmod is apply_typmod from numeric.c: it rounds v to scale digits
+// after the decimal point and checks that it fits in precision digits.
+func applyTypmod(v numericVar, precision, scale int) (numericVar, error) {
+	v = roundVar(v, scale, true)
+
+	if v.coef.Sign() != 0 {
+		intDigits := len(new(big.Int).Abs(v.coef).String()) - v.scale
+		if intDigits > precision-scale {
+			return numericVar{}, errNumericFieldOverflow
+		}
+	}
+
+	return v, nil
+}
+
+// MoneyIn parses s the way PostgreSQL's money input function does in the
+// locale loc: an optional currency symbol and sign, or parentheses for a
+// negative amount, around digits with thousands separators and a decimal
+// point. The result has the number of fraction digits of money in loc,
+// rounding half up on the next digit. Like money, it must fit in an int64
+// count of the smallest currency unit.
+func MoneyIn(s string, loc NumericLocale) (decimal128.Decimal, error) {
+	fpoint := 2
+	if loc.MonDecimalPoint != "" && loc.FracDigits >= 0 && loc.FracDigits <= 10 {
+		fpoint = loc.FracDigits
+	}
+
+	// The decimal point must be a single byte.
+	dsymbol := byte('.')
+	if len(loc.MonDecimalPoint) == 1 {
+		dsymbol = loc.MonDecimalPoint[0]
+	}
+
+	ssymbol := loc.MonThousandsSep
+	if ssymbol == "" {
+		ssymbol = ","
+		if dsymbol == ',' {
+			ssymbol = "."
+		}
+	}
+
+	csymbol := cmp.Or(loc.CurrencySymbol, "$")
+	psymbol := cmp.Or(loc.PositiveSign, "+")
+	nsymbol := cmp.Or(loc.NegativeSign, "-")
+
+	str := s
+	skipCurrency := func() {
+		str = strings.TrimLeft(str, " \t\n\r\f\v")
+		str = strings.TrimPrefix(str, csymbol)
+		str = strings.TrimLeft(str, " \t\n\r\f\v")
+	}
+
+	skipCurrency()
+
+	neg := false
+	switch {
+	case strings.HasPrefix(str, nsymbol):
+		neg = true
+		str = str[len(nsymbol):]
+	case strings.HasPrefix(str, "("):
+		neg = true
+		str = str[1:]
+	case strings.HasPrefix(str, psymbol):
+		str = str[len(psymbol):]
+	}
+
+	skipCurrency()
+
+	value := new(big.Int)
+	dec := 0
+	seenDot := false
+digits:
+	for ; str != ""; str = str[1:] {
+		switch c := str[0]; {
+		case isDigit(c) && (!seenDot || dec < fpoint):
+			value.Mul(value, bigTen).Add(value, big.NewInt(int64(c-'0')))
+			if seenDot {
+				dec++
+			}
+		case c == dsymbol && !seenDot:
+			seenDot = true
+		case strings.HasPrefix(str, ssymbol):
+			str = str[len(ssymbol)-1:]
+		default:
+			break digits
+		}
+	}
+
+	// Round off if there is another digit.
+	if str != "" && isDigit(str[0]) && str[0] >= '5' {
+		value.Add(value, big.NewInt(1))
+	}
+
+	for ; dec < fpoint; dec++ {
+		value.Mul(value, bigTen)
+	}
+
+	// Only trailing digits, whitespace, a closing parenthesis, a trailing
+	// sign and a currency symbol may follow.
+	str = strings.TrimLeft(str, "0123456789")
+	for str != "" {
+		switch {
+		case strings.ContainsRune(" \t\n\r\f\v)", rune(str[0])):
+			str = str[1:]
+		case strings.HasPrefix(str, nsymbol):
+			neg = true
+			str = str[len(nsymbol):]
+		case strings.HasPrefix(str, psymbol):
+			str = str[len(psymbol):]
+		case strings.HasPrefix(str, csymbol):
+			str = str[len(csymbol):]
+		default:
+			return decimal128.Decimal{}, fmt.Errorf("invalid input syntax for type money: %q", s)
+		}
+	}
+
+	if neg {
+		value.Neg(value)
+	}
+
+	// The range of money is that of int64, whose negative range is larger.
+	if !value.IsInt64() {
+		return decimal128.Decimal{}, fmt.Errorf("value %q is out of range for type money", s)
+	}
+
+	return numericVar{coef: value, scale: fpoint}.decimal()
+}
@@ -0,0 +1,147 @@
+package decimal_test
+
+import (
+	"context"
+	"testing"
+
+	pgxdecimal "github.com/ingothierack/pgx-woodsbury-decimal128"
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/require"
+)
+
+// toNumberTests holds the text output of PostgreSQL for SELECT to_number(s,
+// format) in the C locale, or NULL. TestNumericToNumberMatchServer checks them
+// against a live server.
+var toNumberTests = []struct {
+	value    string
+	format   string
+	expected string
+}{
+	{"12,454.8-", "99G999D9S", "-12454.8"},
+	{"-34,338,492", "99G999G999", "-34338492"},
+	{"-34,338,492.654,878", "99G999G999D999G999", "-34338492.654878"},
+	{"5.01-", "FM9.999999MI", "-5.01"},
+	{"<123>", "999PR", "-123"},
+	{"+485", "S999", "485"},
+	{"1,234.56", "9G999D99", "1234.56"},
+	{"123", "99", "12"},
+	{"0.01", "99.99", "0.01"},
+	{".5", "9.99", "0.5"},
+	{"1.25", "9.9", "1.2"},
+	{"12.", "99.99", "12"},
+	{"00012", "99999", "12"},
+	{"1 2 3", "9 9 9", "123"},
+	{"Total: 42", `"Total:"999`, "42"},
+	{"12400", "99V999", "12.4000000000000000000"},
+	{"42", "", "NULL"},
+}
+
+func TestNumericToNumber(t *testing.T) {
+	text := nullDecimalText(t)
+	for _, tt := range toNumberTests {
+		require.Equal(t, tt.expected, text(pgxdecimal.NumericToNumber(tt.value, tt.format)), "%s %s", tt.value, tt.format)
+	}
+}
+
+func TestNumericToNumberLocale(t *testing.T) {
+	loc := pgxdecimal.NumericLocale{DecimalPoint: ",", ThousandsSep: ".", CurrencySymbol: "€"}
+
+	text := nullDecimalText(t)
+	require.Equal(t, "1234.56", text(pgxdecimal.NumericToNumberLocale("1.234,56 €", "9G999D99", loc)))
+	require.Equal(t, "1234.56", text(pgxdecimal.NumericToNumberLocale("€1.234,56", "L9G999D99", loc)))
+
+	// Only D and G follow the locale.
+	require.Equal(t, "1.23", text(pgxdecimal.NumericToNumberLocale("1.23", "9.99", loc)))
+}
+
+func TestNumericToNumberErrors(t *testing.T) {
+	for _, tt := range []struct {
+		value  string
+		format string
+	}{
+		{"abc", "999"},
+		{"12345", "99.99"},
+		{"-", "999"},
+		{"XII", "RN"},
+		{"1e3", "9EEEE"},
+		{"1", "9.9.9"},
+	} {
+		_, err := pgxdecimal.NumericToNumber(tt.value, tt.format)
+		require.Error(t, err, "%s %s", tt.value, tt.format)
+	}
+}
+
+// moneyInTests holds the output of PostgreSQL for SELECT s::money::numeric with
+// lc_monetary set to C.
+var moneyInTests = []struct {
+	value    string
+	expected string
+}{
+	{"$1,234.56", "1234.56"},
+	{"(1,234.56)", "-1234.56"},
+	{"-$12.3", "-12.30"},
+	{"$ -12.3", "-12.30"},
+	{"12.345", "12.35"},
+	{"12.344", "12.34"},
+	{"  42 ", "42.00"},
+	{"42-", "-42.00"},
+	{"1,000,000", "1000000.00"},
+	{"-92233720368547758.08", "-92233720368547758.08"},
+}
+
+func TestMoneyIn(t *testing.T) {
+	for _, tt := range moneyInTests {
+		d, err := pgxdecimal.MoneyIn(tt.value, pgxdecimal.NumericLocale{})
+		require.NoError(t, err, tt.value)
+		require.Equal(t, tt.expected, pgxdecimal.Decimal(d).String(), tt.value)
+	}
+}
+
+func TestMoneyInLocale(t *testing.T) {
+	loc := pgxdecimal.NumericLocale{MonDecimalPoint: ",", MonThousandsSep: ".", FracDigits: 2, CurrencySymbol: "€"}
+
+	d, err := pgxdecimal.MoneyIn("1.234,56 €", loc)
+	require.NoError(t, err)
+	require.Equal(t, "1234.56", pgxdecimal.Decimal(d).String())
+
+	d, err = pgxdecimal.MoneyIn("-€ 0,5", loc)
+	require.NoError(t, err)
+	require.Equal(t, "-0.50", pgxdecimal.Decimal(d).String())
+
+	// A locale like ja_JP has no fraction digits.
+	d, err = pgxdecimal.MoneyIn("¥1,235", pgxdecimal.NumericLocale{MonDecimalPoint: ".", CurrencySymbol: "¥"})
+	require.NoError(t, err)
+	require.Equal(t, "1235", pgxdecimal.Decimal(d).String())
+}
+
+func TestMoneyInErrors(t *testing.T) {
+	for _, s := range []string{"abc", "12 34", "$1.2.3", "92233720368547758.08", "-92233720368547758.09"} {
+		_, err := pgxdecimal.MoneyIn(s, pgxdecimal.NumericLocale{})
+		require.Error(t, err, s)
+	}
+}
+
+func TestNumericToNumberMatchServer(t *testing.T) {
+	defaultConnTestRunner.RunTest(context.Background(), t, func(ctx context.Context, t testing.TB, conn *pgx.Conn) {
+		for _, tt := range toNumberTests {
+			var result string
+			err := conn.QueryRow(ctx, "select coalesce(to_number($1, $2)::text, 'NULL')", tt.value, tt.format).Scan(&result)
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, result, "%s %s", tt.value, tt.format)
+		}
+	})
+}
+
+func TestMoneyInMatchServer(t *testing.T) {
+	defaultConnTestRunner.RunTest(context.Background(), t, func(ctx context.Context, t testing.TB, conn *pgx.Conn) {
+		_, err := conn.Exec(ctx, "set lc_monetary to 'C'")
+		require.NoError(t, err)
+
+		for _, tt := range moneyInTests {
+			var result string
+			err := conn.QueryRow(ctx, "select $1::text::money::numeric::text", tt.value).Scan(&result)
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, result, tt.value)
+		}
+	})
+}
@@ -0,0 +1,87 @@
+package decimal
+
+import (
+	"fmt"
+
+	"github.com/ingothierack/decimal128"
+)
+
+// varHdrSz is VARHDRSZ, which PostgreSQL adds to numeric type modifiers.
+const varHdrSz = 4
+
+// NumericTypmod returns the type modifier of numeric(precision, scale), as
+// found in pgconn.FieldDescription.TypeModifier. It returns an error if
+// precision is not between 1 and 1000 or scale is not between -1000 and 1000,
+// the limits of PostgreSQL 15 and later.
+func NumericTypmod(precision, scale int) (int32, error) {
+	if precision < 1 || precision > 1000 {
+		return 0, fmt.Errorf("NUMERIC precision %d must be between 1 and 1000", precision)
+	}
+	if scale < -1000 || scale > 1000 {
+		return 0, fmt.Errorf("NUMERIC scale %d must be between -1000 and 1000", scale)
+	}
+
+	return int32(precision<<16|scale&0x7ff) + varHdrSz, nil
+}
+
+// numericTypmodFields returns the precision and scale of typmod, and false
+// if typmod is -1 or otherwise does not constrain the value.
+func numericTypmodFields(typmod int32) (precision, scale int, ok bool) {
+	if typmod < varHdrSz {
+		return 0, 0, false
+	}
+
+	typmod -= varHdrSz
+	return int(typmod >> 16 & 0xffff), int(typmod&0x7ff^1024) - 1024, true
+}
+
+// CheckNumericTypmod reports whether PostgreSQL accepts d in a column of
+// type modifier typmod, returning the "numeric field overflow" error of
+// apply_typmod if it does not. Values with more fraction digits than the
+// scale are accepted, since the server rounds them, unless the rounding
+// overflows. NaN is always accepted and infinities only without a typmod.
+func CheckNumericTypmod(d decimal128.Decimal, typmod int32) error {
+	_, err := roundNumericTypmod(d, typmod)
+	return err
+}
+
+// roundNumericTypmod is CheckNumericTypmod that also returns d rounded to the
+// scale of typmod, as the server stores it. Values with no more fraction
+// digits than the scale are returned unchanged.
+func roundNumericTypmod(d decimal128.Decimal, typmod int32) (decimal128.Decimal, error) {
+	precision, scale, ok := numericTypmodFields(typmod)
+	if !ok || d.IsNaN() {
+		return d, nil
+	}
+	if d.IsInf(0) {
+		return decimal128.Decimal{}, errNumericFieldOverflow
+	}
+
+	var sigBuf [16]byte
+	_, _, sig, exp := d.Decompose(sigBuf[:0])
+	hi, lo := uint128FromBytes(sig)
+	if hi|lo == 0 {
+		return d, nil
+	}
+
+	if int(exp) < -scale {
+		v, err := applyTypmod(newNumericVar(d), precision, scale)
+		if err != nil {
+			return decimal128.Decimal{}, err
+		}
+		return v.decimal()
+	}
+
+	// Without rounding, |d| must be below 10^(precision - scale).
+	n := precision - scale - int(exp)
+	if n <= 0 {
+		return decimal128.Decimal{}, errNumericFieldOverflow
+	}
+	if n <= 38 {
+		if phi, plo := pow10Uint128(n); !less128(hi, lo, phi, plo) {
+			return decimal128.Decimal{}, errNumericFieldOverflow
+		}
+	}
+
+	return d, nil
+}
@@ -0,0 +1,198 @@
+package decimal
+
+import (
+	"encoding/binary"
+	"errors"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/ingothierack/decimal128"
+)
+
+// Tags of sort key encodings, in ascending order.
+const (
+	sortKeyNullFirst byte = 0x01 + iota
+	sortKeyNegInf
+	sortKeyNeg
+	sortKeyZero
+	sortKeyPos
+	sortKeyPosInf
+	sortKeyNaN
+	sortKeyNullLast
+)
+
+// sortKeyExpBias is added to exponents to make them unsigned.
+const sortKeyExpBias = 0x8000
+
+var errInvalidSortKey = errors.New("invalid numeric sort key")
+
+// AppendSortKey appends to buf an encoding of d whose bytewise order is the
+// order o of PostgreSQL numeric values, so that bytes.Compare on keys sorts
+// like ORDER BY. The encoding is self-delimiting: keys can be followed by other
+// key parts and still compare correctly. Equal values such as 1 and 1.0 have
+// the same key, so the scale is not preserved.
+func AppendSortKey(buf []byte, d decimal128.Decimal, o SortOrder) []byte {
+	start := len(buf)
+
+	switch {
+	case d.IsNaN():
+		buf = append(buf, sortKeyNaN)
+	case d.IsInf(1):
+		buf = append(buf, sortKeyPosInf)
+	case d.IsInf(-1):
+		buf = append(buf, sortKeyNegInf)
+	case d.IsZero():
+		buf = append(buf, sortKeyZero)
+	default:
+		buf = appendSortKeyFinite(buf, d)
+	}
+
+	if o.Desc {
+		invertBytes(buf[start:])
+	}
+
+	return buf
+}
+
+// AppendNullSortKey is like AppendSortKey for a NullDecimal, placing NULL
+// values as o.Nulls says.
+func AppendNullSortKey(buf []byte, d NullDecimal, o SortOrder) []byte {
+	if d.Valid {
+		return AppendSortKey(buf, d.Decimal, o)
+	}
+
+	// Descending keys are inverted, which reverses the order of the tags.
+	tag := sortKeyNullLast
+	if nullsFirst := o.nullCmp() < 0; nullsFirst != o.Desc {
+		tag = sortKeyNullFirst
+	}
+	if o.Desc {
+		tag = ^tag
+	}
+
+	return append(buf, tag)
+}
+
+// appendSortKeyFinite appends the encoding of a finite non-zero value: its
+// sign, the exponent E with |d| = 0.d1d2...dn * 10^E and d1 not zero, and the
+// digits in pairs, each stored as 1 to 100 and terminated by 0. The exponent
+// and digits of negative values are inverted so that larger magnitudes sort
+// first.
+func appendSortKeyFinite(buf []byte, d decimal128.Decimal) []byte {
+	_, neg, sig, exp := d.Decompose(nil)
+	coef := new(big.Int).SetBytes(sig).String()
+	digits := strings.TrimRight(coef, "0")
+	e := len(coef) + int(exp)
+
+	tag := sortKeyPos
+	if neg {
+		tag = sortKeyNeg
+	}
+	buf = append(buf, tag)
+
+	start := len(buf)
+	buf = binary.BigEndian.AppendUint16(buf, uint16(e+sortKeyExpBias))
+	for i := 0; i < len(digits); i += 2 {
+		pair := int(digits[i]-'0') * 10
+		if i+1 < len(digits) {
+			pair += int(digits[i+1] - '0')
+		}
+		buf = append(buf, byte(pair+1))
+	}
+	buf = append(buf, 0)
+
+	if neg {
+		invertBytes(buf[start:])
+	}
+
+	return buf
+}
+
+func invertBytes(b []byte) {
+	for i := range b {
+		b[i] = ^b[i]
+	}
+}
+
+// DecodeSortKey decodes a key written by AppendSortKey or AppendNullSortKey
+// with the same order o from the start of b. It returns the value, in
+// canonical form without trailing zeros, and the rest of b.
+func DecodeSortKey(b []byte, o SortOrder) (NullDecimal, []byte, error) {
+	if len(b) == 0 {
+		return NullDecimal{}, nil, errInvalidSortKey
+	}
+
+	flip := byte(0)
+	if o.Desc {
+		flip = 0xff
+	}
+
+	var d decimal128.Decimal
+	switch b[0] ^ flip {
+	case sortKeyNullFirst, sortKeyNullLast:
+		return NullDecimal{}, b[1:], nil
+	case sortKeyNegInf:
+		d = decimal128.Inf(-1)
+	case sortKeyZero:
+		d = decimal128.FromInt64(0)
+	case sortKeyPosInf:
+		d = decimal128.Inf(1)
+	case sortKeyNaN:
+		d = decimal128.NaN()
+	case sortKeyNeg, sortKeyPos:
+		return decodeSortKeyFinite(b, flip)
+	default:
+		return NullDecimal{}, nil, errInvalidSortKey
+	}
+
+	return NullDecimal{Decimal: d, Valid: true}, b[1:], nil
+}
+
+func decodeSortKeyFinite(b []byte, flip byte) (NullDecimal, []byte, error) {
+	neg := b[0]^flip == sortKeyNeg
+	if neg {
+		flip = ^flip
+	}
+
+	if len(b) < 4 {
+		return NullDecimal{}, nil, errInvalidSortKey
+	}
+	e := int(uint16(b[1]^flip)<<8|uint16(b[2]^flip)) - sortKeyExpBias
+
+	var sb strings.Builder
+	if neg {
+		sb.WriteByte('-')
+	}
+	sb.WriteString("0.")
+
+	i := 3
+	for ; ; i++ {
+		if i == len(b) {
+			return NullDecimal{}, nil, errInvalidSortKey
+		}
+
+		pair := b[i] ^ flip
+		if pair == 0 {
+			break
+		}
+		if pair > 100 {
+			return NullDecimal{}, nil, errInvalidSortKey
+		}
+		sb.WriteByte('0' + (pair-1)/10)
+		sb.WriteByte('0' + (pair-1)%10)
+	}
+	if i == 3 {
+		return NullDecimal{}, nil, errInvalidSortKey
+	}
+
+	sb.WriteByte('e')
+	sb.WriteString(strconv.Itoa(e))
+
+	d, err := decimal128.Parse(sb.String())
+	if err != nil || d.IsInf(0) {
+		return NullDecimal{}, nil, errInvalidSortKey
+	}
+
+	return NullDecimal{Decimal: d.Canonical(), Valid: true}, b[i+1:], nil
+}
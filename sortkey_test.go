@@ -0,0 +1,112 @@
+package decimal_test
+
+import (
+	"bytes"
+	"slices"
+	"testing"
+
+	"github.com/ingothierack/decimal128"
+	pgxdecimal "github.com/ingothierack/pgx-woodsbury-decimal128"
+	"github.com/stretchr/testify/require"
+)
+
+// sortKeyTestValues are in ascending PostgreSQL order, with "" for NULL.
+var sortKeyTestValues = []string{
+	"-Infinity", "-1e6000", "-123456789012345678901234567890.1234", "-100", "-99.99", "-10.1", "-10", "-1.01",
+	"-1", "-0.1", "-1e-6000", "0", "1e-6100", "0.000123", "0.1", "0.10001", "0.11", "1", "1.5", "9", "10",
+	"10.01", "99", "100", "1e30", "9999999999999999999999999999999999e6111", "Infinity", "NaN",
+}
+
+func sortKeyTestNullDecimals() []pgxdecimal.NullDecimal {
+	values := make([]pgxdecimal.NullDecimal, len(sortKeyTestValues))
+	for i, s := range sortKeyTestValues {
+		values[i] = pgxdecimal.NullDecimal{Decimal: decimal128.MustParse(s), Valid: true}
+	}
+	return values
+}
+
+func TestSortKeyOrder(t *testing.T) {
+	for _, order := range []pgxdecimal.SortOrder{
+		{},
+		{Nulls: pgxdecimal.NullsFirst},
+		{Desc: true},
+		{Desc: true, Nulls: pgxdecimal.NullsLast},
+	} {
+		values := append(sortKeyTestNullDecimals(), pgxdecimal.NullDecimal{})
+
+		keys := make([][]byte, len(values))
+		for i, v := range values {
+			keys[i] = pgxdecimal.AppendNullSortKey(nil, v, order)
+		}
+
+		for i := range values {
+			for j := range values {
+				require.Equal(t,
+					order.CompareNullDecimal(values[i], values[j]),
+					bytes.Compare(keys[i], keys[j]),
+					"%v: %s and %s", order, pgxdecimal.Decimal(values[i].Decimal), pgxdecimal.Decimal(values[j].Decimal),
+				)
+			}
+		}
+	}
+}
+
+func TestSortKeyRoundTrip(t *testing.T) {
+	for _, order := range []pgxdecimal.SortOrder{{}, {Desc: true}} {
+		var buf []byte
+		values := append(sortKeyTestNullDecimals(), pgxdecimal.NullDecimal{})
+		for _, v := range values {
+			buf = pgxdecimal.AppendNullSortKey(buf, v, order)
+		}
+
+		for _, v := range values {
+			var d pgxdecimal.NullDecimal
+			var err error
+			d, buf, err = pgxdecimal.DecodeSortKey(buf, order)
+			require.NoError(t, err)
+			require.Equal(t, v.Key(), d.Key())
+		}
+		require.Empty(t, buf)
+	}
+
+	// The scale is not preserved.
+	key := pgxdecimal.AppendSortKey(nil, decimal128.MustParse("-12.500"), pgxdecimal.SortOrder{})
+	require.Equal(t, key, pgxdecimal.AppendSortKey(nil, decimal128.MustParse("-12.5"), pgxdecimal.SortOrder{}))
+
+	d, rest, err := pgxdecimal.DecodeSortKey(key, pgxdecimal.SortOrder{})
+	require.NoError(t, err)
+	require.Empty(t, rest)
+	require.Equal(t, "-12.5", pgxdecimal.Decimal(d.Decimal).String())
+}
+
+func TestSortKeyComposite(t *testing.T) {
+	type row struct {
+		amount string
+		id     byte
+	}
+	rows := []row{{"10", 2}, {"9.5", 3}, {"10", 1}, {"-1", 9}, {"NaN", 0}}
+
+	key := func(r row) []byte {
+		return append(pgxdecimal.AppendSortKey(nil, decimal128.MustParse(r.amount), pgxdecimal.SortOrder{}), r.id)
+	}
+	slices.SortFunc(rows, func(a, b row) int {
+		return bytes.Compare(key(a), key(b))
+	})
+	require.Equal(t, []row{{"-1", 9}, {"9.5", 3}, {"10", 1}, {"10", 2}, {"NaN", 0}}, rows)
+}
+
+func TestDecodeSortKeyErrors(t *testing.T) {
+	for _, key := range [][]byte{
+		nil,
+		{0x00},
+		{0x09},
+		{0x05, 0x80},
+		{0x05, 0x80, 0x01},
+		{0x05, 0x80, 0x01, 0x00},
+		{0x05, 0x80, 0x01, 0x02},
+		{0x05, 0x80, 0x01, 0x66, 0x00},
+	} {
+		_, _, err := pgxdecimal.DecodeSortKey(key, pgxdecimal.SortOrder{})
+		require.Error(t, err, "%x", key)
+	}
+}
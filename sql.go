@@ -0,0 +1,118 @@
+package decimal
+
+import (
+	"database/sql/driver"
+	"fmt"
+
+	"github.com/ingothierack/decimal128"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const ErrScanType = "cannot scan %T into *decimal128.Decimal"
+
+// Scan implements the database/sql Scanner interface. It accepts string,
+// []byte, int64 and float64 sources.
+func (d *Decimal) Scan(src any) error {
+	if src == nil {
+		return errScanNull
+	}
+
+	dd, err := scanSQLValue(src)
+	if err != nil {
+		return err
+	}
+
+	*d = Decimal(dd)
+	return nil
+}
+
+// Value implements the database/sql/driver Valuer interface. The value is
+// returned as numeric text with its scale preserved.
+func (d Decimal) Value() (driver.Value, error) {
+	return string(appendDecimalText(nil, decimal128.Decimal(d))), nil
+}
+
+// Scan implements the database/sql Scanner interface. A nil source scans into
+// an invalid NullDecimal.
+func (d *NullDecimal) Scan(src any) error {
+	if src == nil {
+		*d = NullDecimal{}
+		return nil
+	}
+
+	dd, err := scanSQLValue(src)
+	if err != nil {
+		return err
+	}
+
+	*d = NullDecimal{Decimal: dd, Valid: true}
+	return nil
+}
+
+// Value implements the database/sql/driver Valuer interface.
+func (d NullDecimal) Value() (driver.Value, error) {
+	if !d.Valid {
+		return nil, nil
+	}
+
+	return Decimal(d.Decimal).Value()
+}
+
+func scanSQLValue(src any) (decimal128.Decimal, error) {
+	switch src := src.(type) {
+	case string:
+		return parseSQLText(src)
+	case []byte:
+		return parseSQLText(string(src))
+	case int64:
+		return decimal128.FromInt64(src), nil
+	case float64:
+		return parseFloat64(src)
+	}
+
+	return decimal128.Decimal{}, fmt.Errorf(ErrScanType, src)
+}
+
+func parseSQLText(s string) (decimal128.Decimal, error) {
+	dd, err := decimal128.Parse(s)
+	if err != nil {
+		return decimal128.Decimal{}, err
+	}
+
+	if dd.IsNaN() {
+		return decimal128.Decimal{}, errScanNaN
+	}
+
+	if dd.IsInf(0) {
+		return decimal128.Decimal{}, fmt.Errorf(ErrScanInf, s)
+	}
+
+	return dd, nil
+}
+
+// DecodeDatabaseSQLValue decodes src the same way DecodeValue does and returns
+// it as numeric text, so database/sql callers see the value a native pgx
+// caller would get. NaN and infinite values keep their PostgreSQL spelling,
+// and values that do not fit in a decimal128 fail with ErrNumericOverflow.
+func (c NumericCodec) DecodeDatabaseSQLValue(tm *pgtype.Map, oid uint32, format int16, src []byte) (driver.Value, error) {
+	if src == nil {
+		return nil, nil
+	}
+
+	v, err := c.NumericCodec.DecodeValue(tm, oid, format, src)
+	if err != nil {
+		return nil, err
+	}
+
+	n := v.(pgtype.Numeric)
+	if n.NaN || n.InfinityModifier != pgtype.Finite {
+		return n.Value()
+	}
+
+	dd, err := composeDecimal(n)
+	if err != nil {
+		return nil, err
+	}
+
+	return Decimal(dd).Value()
+}
@@ -0,0 +1,126 @@
+package decimal_test
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"testing"
+
+	"github.com/ingothierack/decimal128"
+	pgxdecimal "github.com/ingothierack/pgx-woodsbury-decimal128"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	_ sql.Scanner   = (*pgxdecimal.Decimal)(nil)
+	_ driver.Valuer = pgxdecimal.Decimal{}
+	_ sql.Scanner   = (*pgxdecimal.NullDecimal)(nil)
+	_ driver.Valuer = pgxdecimal.NullDecimal{}
+)
+
+func TestDecimalSQLScan(t *testing.T) {
+	for _, tt := range []struct {
+		src      any
+		expected decimal128.Decimal
+	}{
+		{src: "123.456", expected: decimal128.MustParse("123.456")},
+		{src: []byte("-0.000012345"), expected: decimal128.MustParse("-0.000012345")},
+		{src: int64(-42), expected: decimal128.FromInt64(-42)},
+		{src: float64(1.5), expected: decimal128.MustParse("1.5")},
+	} {
+		var d pgxdecimal.Decimal
+		require.NoError(t, d.Scan(tt.src))
+		require.True(t, decimal128.Decimal(d).Equal(tt.expected), "%v", tt.src)
+
+		var nd pgxdecimal.NullDecimal
+		require.NoError(t, nd.Scan(tt.src))
+		require.True(t, nd.Valid)
+		require.True(t, nd.Decimal.Equal(tt.expected), "%v", tt.src)
+	}
+}
+
+func TestDecimalSQLScanErrors(t *testing.T) {
+	var d pgxdecimal.Decimal
+	require.EqualError(t, d.Scan(nil), pgxdecimal.ErrScanNull)
+	require.EqualError(t, d.Scan("NaN"), pgxdecimal.ErrScanNaN)
+	require.Error(t, d.Scan("Infinity"))
+	require.Error(t, d.Scan("abc"))
+	require.EqualError(t, d.Scan(true), "cannot scan bool into *decimal128.Decimal")
+
+	nd := pgxdecimal.NullDecimal{Decimal: decimal128.FromInt64(1), Valid: true}
+	require.NoError(t, nd.Scan(nil))
+	require.False(t, nd.Valid)
+}
+
+func TestDecimalSQLValue(t *testing.T) {
+	v, err := pgxdecimal.Decimal(decimal128.MustParse("1.00")).Value()
+	require.NoError(t, err)
+	require.Equal(t, "1.00", v)
+
+	v, err = pgxdecimal.Decimal(decimal128.MustParse("-9345678901234567890.123456789012345")).Value()
+	require.NoError(t, err)
+	require.Equal(t, "-9345678901234567890.123456789012345", v)
+
+	v, err = pgxdecimal.Decimal(decimal128.NaN()).Value()
+	require.NoError(t, err)
+	require.Equal(t, "NaN", v)
+
+	v, err = pgxdecimal.Decimal(decimal128.Inf(-1)).Value()
+	require.NoError(t, err)
+	require.Equal(t, "-Infinity", v)
+
+	v, err = pgxdecimal.NullDecimal{}.Value()
+	require.NoError(t, err)
+	require.Nil(t, v)
+
+	v, err = pgxdecimal.NullDecimal{Decimal: decimal128.MustParse("12.5"), Valid: true}.Value()
+	require.NoError(t, err)
+	require.Equal(t, "12.5", v)
+}
+
+func TestCodecDecodeDatabaseSQLValue(t *testing.T) {
+	m := pgtype.NewMap()
+	pgxdecimal.Register(m)
+	codec := pgxdecimal.NumericCodec{}
+
+	for _, tt := range []struct {
+		value    pgtype.Numeric
+		expected driver.Value
+	}{
+		{value: numericFromString(t, "123456789012345.123456789012"), expected: "123456789012345.123456789012"},
+		{value: numericFromString(t, "-0.000012345"), expected: "-0.000012345"},
+		{value: pgtype.Numeric{NaN: true, Valid: true}, expected: "NaN"},
+		{value: pgtype.Numeric{InfinityModifier: pgtype.NegativeInfinity, Valid: true}, expected: "-Infinity"},
+	} {
+		buf, err := m.Encode(pgtype.NumericOID, pgtype.BinaryFormatCode, tt.value, nil)
+		require.NoError(t, err)
+
+		v, err := codec.DecodeDatabaseSQLValue(m, pgtype.NumericOID, pgtype.BinaryFormatCode, buf)
+		require.NoError(t, err)
+		require.Equal(t, tt.expected, v)
+	}
+
+	v, err := codec.DecodeDatabaseSQLValue(m, pgtype.NumericOID, pgtype.BinaryFormatCode, nil)
+	require.NoError(t, err)
+	require.Nil(t, v)
+
+	for _, format := range []int16{pgtype.TextFormatCode, pgtype.BinaryFormatCode} {
+		buf, err := m.Encode(pgtype.NumericOID, format, numericFromString(t, "1234567890123456789012345678901234567890"), nil)
+		require.NoError(t, err)
+
+		_, err = codec.DecodeDatabaseSQLValue(m, pgtype.NumericOID, format, buf)
+		require.EqualError(t, err, pgxdecimal.ErrNumericOverflow)
+	}
+
+	buf, err := m.Encode(pgtype.NumericOID, pgtype.TextFormatCode, numericFromString(t, "1234567890123456789012345678901234000000"), nil)
+	require.NoError(t, err)
+	v, err = codec.DecodeDatabaseSQLValue(m, pgtype.NumericOID, pgtype.TextFormatCode, buf)
+	require.NoError(t, err)
+	require.Equal(t, "1234567890123456789012345678901234000000", v)
+}
+
+func numericFromString(t testing.TB, s string) pgtype.Numeric {
+	var n pgtype.Numeric
+	require.NoError(t, n.Scan(s))
+	return n
+}